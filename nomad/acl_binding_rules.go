@@ -0,0 +1,176 @@
+package nomad
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-bexpr"
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/pointerstructure"
+)
+
+// validACLBindName matches the names Nomad accepts for ACL roles and policies
+var validACLBindName = regexp.MustCompile("^[a-zA-Z0-9-]{1,128}$")
+
+// aclBindNameVariable matches the ${value.<name>} variables of a bind name
+var aclBindNameVariable = regexp.MustCompile(`\$\{\s*([^}\s]+)\s*\}`)
+
+// aclAuthClaims holds the claims of an identity once mapped through the
+// ClaimMappings and ListClaimMappings of an auth method. It is the data
+// binding rule selectors are evaluated against, e.g. "engineering in list.groups".
+type aclAuthClaims struct {
+	Value map[string]string   `bexpr:"value" json:"value"`
+	List  map[string][]string `bexpr:"list" json:"list"`
+}
+
+// parseACLAuthClaims decodes the claims of an identity, keeping numbers as
+// they were written.
+func parseACLAuthClaims(claims string) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(claims)))
+	decoder.UseNumber()
+
+	var parsed map[string]interface{}
+	if err := decoder.Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("claims must be a JSON object: %v", err)
+	}
+	return parsed, nil
+}
+
+// mapACLAuthClaims maps the claims of an identity through the claim mappings of
+// an auth method, as Nomad does on login. Claims missing from the identity are
+// skipped.
+func mapACLAuthClaims(config *api.ACLAuthMethodConfig, claims map[string]interface{}) (*aclAuthClaims, error) {
+	mapped := &aclAuthClaims{
+		Value: map[string]string{},
+		List:  map[string][]string{},
+	}
+	if config == nil {
+		return mapped, nil
+	}
+
+	for source, target := range config.ClaimMappings {
+		raw := getACLAuthClaim(claims, source)
+		if raw == nil {
+			continue
+		}
+		value, ok := stringifyACLAuthClaim(raw)
+		if !ok {
+			return nil, fmt.Errorf("error converting claim %q to string from unknown type %T", source, raw)
+		}
+		mapped.Value[target] = value
+	}
+
+	for source, target := range config.ListClaimMappings {
+		raw := getACLAuthClaim(claims, source)
+		if raw == nil {
+			continue
+		}
+		var rawList []interface{}
+		switch v := raw.(type) {
+		case []interface{}:
+			rawList = v
+		case string:
+			rawList = []interface{}{v}
+		default:
+			return nil, fmt.Errorf("%q list claim could not be converted to string list", source)
+		}
+		list := make([]string, 0, len(rawList))
+		for _, item := range rawList {
+			value, ok := stringifyACLAuthClaim(item)
+			if !ok {
+				return nil, fmt.Errorf("value %v in %q list claim could not be parsed as string", item, source)
+			}
+			if value == "" {
+				continue
+			}
+			list = append(list, value)
+		}
+		mapped.List[target] = list
+	}
+
+	return mapped, nil
+}
+
+// getACLAuthClaim returns a claim by name, or by JSON pointer if the name
+// starts with a slash, e.g. "/user/groups".
+func getACLAuthClaim(claims map[string]interface{}, name string) interface{} {
+	if !strings.HasPrefix(name, "/") {
+		return claims[name]
+	}
+	value, err := pointerstructure.Get(claims, name)
+	if err != nil {
+		return nil
+	}
+	return value
+}
+
+func stringifyACLAuthClaim(raw interface{}) (string, bool) {
+	switch v := raw.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case json.Number:
+		return v.String(), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case nil:
+		return "", true
+	}
+	return "", false
+}
+
+// matchACLBindingRuleSelector returns true if the selector matches the mapped
+// claims. As in Nomad, an empty selector matches every identity and a selector
+// that fails to evaluate matches none.
+func matchACLBindingRuleSelector(selector string, claims *aclAuthClaims) (bool, error) {
+	if selector == "" {
+		return true, nil
+	}
+	evaluator, err := bexpr.CreateEvaluator(selector)
+	if err != nil {
+		return false, err
+	}
+	return evaluator.Evaluate(claims)
+}
+
+// computeACLBindName interpolates the ${value.<name>} variables of a bind name
+// with the lowercased mapped claims and reports whether the result is valid
+// for the bind type. Claims mapped by the auth method but missing from the
+// identity interpolate to an empty string.
+func computeACLBindName(config *api.ACLAuthMethodConfig, bindType, bindName string, claims *aclAuthClaims) (string, bool, error) {
+	vars := map[string]string{}
+	if config != nil {
+		for _, target := range config.ClaimMappings {
+			vars["value."+target] = ""
+		}
+	}
+	for target, value := range claims.Value {
+		vars["value."+target] = strings.ToLower(value)
+	}
+
+	var err error
+	name := aclBindNameVariable.ReplaceAllStringFunc(bindName, func(match string) string {
+		variable := aclBindNameVariable.FindStringSubmatch(match)[1]
+		value, ok := vars[variable]
+		if !ok && err == nil {
+			err = fmt.Errorf("unknown variable accessed: %s", variable)
+		}
+		return value
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	switch bindType {
+	case api.ACLBindingRuleBindTypeRole, api.ACLBindingRuleBindTypePolicy:
+		return name, validACLBindName.MatchString(name), nil
+	case api.ACLBindingRuleBindTypeManagement:
+		return name, name == "", nil
+	}
+	return "", false, fmt.Errorf("unknown binding rule bind type: %s", bindType)
+}
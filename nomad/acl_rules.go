@@ -0,0 +1,316 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/hcl"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+// ACL policy dispositions, as used by the policy shorthand
+const (
+	aclPolicyDeny  = "deny"
+	aclPolicyRead  = "read"
+	aclPolicyList  = "list"
+	aclPolicyWrite = "write"
+	aclPolicyScale = "scale"
+)
+
+// ACL rule scopes, one per block type of a policy
+const (
+	aclScopeNamespace  = "namespace"
+	aclScopeVariables  = "variables"
+	aclScopeHostVolume = "host_volume"
+	aclScopeAgent      = "agent"
+	aclScopeNode       = "node"
+	aclScopeOperator   = "operator"
+	aclScopeQuota      = "quota"
+	aclScopePlugin     = "plugin"
+)
+
+// aclPolicyRules is the decoded form of the rules of an ACL policy. It mirrors
+// the policy specification of Nomad, with the capabilities of each rule
+// expanded from the policy shorthand.
+type aclPolicyRules struct {
+	Namespaces  []*aclNamespaceRule  `hcl:"namespace,expand"`
+	HostVolumes []*aclHostVolumeRule `hcl:"host_volume,expand"`
+	Agent       *aclRule             `hcl:"agent"`
+	Node        *aclRule             `hcl:"node"`
+	Operator    *aclRule             `hcl:"operator"`
+	Quota       *aclRule             `hcl:"quota"`
+	Plugin      *aclRule             `hcl:"plugin"`
+}
+
+type aclNamespaceRule struct {
+	Name         string            `hcl:",key"`
+	Policy       string            `hcl:"policy"`
+	Capabilities []string          `hcl:"capabilities"`
+	Variables    *aclVariablesRule `hcl:"variables"`
+}
+
+type aclVariablesRule struct {
+	Paths []*aclVariablesPathRule `hcl:"path,expand"`
+}
+
+type aclVariablesPathRule struct {
+	Path         string   `hcl:",key"`
+	Capabilities []string `hcl:"capabilities"`
+}
+
+type aclHostVolumeRule struct {
+	Name         string   `hcl:",key"`
+	Policy       string   `hcl:"policy"`
+	Capabilities []string `hcl:"capabilities"`
+}
+
+type aclRule struct {
+	Policy       string   `hcl:"policy"`
+	Capabilities []string `hcl:"-"`
+}
+
+// aclCapability is a single capability granted by a policy.
+type aclCapability struct {
+	Scope        string
+	Target       string
+	VariablePath string
+	Policy       string
+	Capability   string
+}
+
+// parseACLPolicyRules decodes the HCL or JSON rules of an ACL policy and
+// expands the policy shorthands into their capability lists.
+func parseACLPolicyRules(rules string) (*aclPolicyRules, error) {
+	parsed := &aclPolicyRules{}
+	if rules == "" {
+		return parsed, nil
+	}
+	if err := hcl.Decode(parsed, rules); err != nil {
+		return nil, fmt.Errorf("failed to parse ACL policy rules: %v", err)
+	}
+
+	for _, ns := range parsed.Namespaces {
+		ns.Capabilities = mergeCapabilities(expandNamespacePolicy(ns.Policy), ns.Capabilities)
+
+		// The read and write shorthands also grant access to all variables
+		if variables := expandNamespaceVariablesPolicy(ns.Policy); variables != nil {
+			if ns.Variables == nil {
+				ns.Variables = &aclVariablesRule{}
+			}
+			ns.Variables.Paths = append(ns.Variables.Paths, variables)
+		}
+		if ns.Variables != nil {
+			for _, path := range ns.Variables.Paths {
+				path.Capabilities = expandVariablesCapabilities(path.Capabilities)
+			}
+		}
+	}
+	for _, hv := range parsed.HostVolumes {
+		hv.Capabilities = mergeCapabilities(expandHostVolumePolicy(hv.Policy), hv.Capabilities)
+	}
+	for _, rule := range []*aclRule{parsed.Agent, parsed.Node, parsed.Operator, parsed.Quota} {
+		if rule != nil {
+			rule.Capabilities = expandCoarsePolicy(rule.Policy)
+		}
+	}
+	if parsed.Plugin != nil {
+		parsed.Plugin.Capabilities = expandPluginPolicy(parsed.Plugin.Policy)
+	}
+
+	return parsed, nil
+}
+
+// Capabilities flattens the rules into one entry per scope, target and
+// capability.
+func (r *aclPolicyRules) Capabilities() []aclCapability {
+	var capabilities []aclCapability
+	for _, ns := range r.Namespaces {
+		for _, capability := range ns.Capabilities {
+			capabilities = append(capabilities, aclCapability{Scope: aclScopeNamespace, Target: ns.Name, Policy: ns.Policy, Capability: capability})
+		}
+		if ns.Variables == nil {
+			continue
+		}
+		for _, path := range ns.Variables.Paths {
+			for _, capability := range path.Capabilities {
+				capabilities = append(capabilities, aclCapability{Scope: aclScopeVariables, Target: ns.Name, VariablePath: path.Path, Capability: capability})
+			}
+		}
+	}
+	for _, hv := range r.HostVolumes {
+		for _, capability := range hv.Capabilities {
+			capabilities = append(capabilities, aclCapability{Scope: aclScopeHostVolume, Target: hv.Name, Policy: hv.Policy, Capability: capability})
+		}
+	}
+	for _, scoped := range []struct {
+		scope string
+		rule  *aclRule
+	}{
+		{aclScopeAgent, r.Agent},
+		{aclScopeNode, r.Node},
+		{aclScopeOperator, r.Operator},
+		{aclScopeQuota, r.Quota},
+		{aclScopePlugin, r.Plugin},
+	} {
+		if scoped.rule == nil {
+			continue
+		}
+		for _, capability := range scoped.rule.Capabilities {
+			capabilities = append(capabilities, aclCapability{Scope: scoped.scope, Policy: scoped.rule.Policy, Capability: capability})
+		}
+	}
+	return capabilities
+}
+
+// expandNamespacePolicy returns the namespace capabilities granted by a
+// policy shorthand.
+func expandNamespacePolicy(policy string) []string {
+	read := []string{
+		"list-jobs",
+		"parse-job",
+		"read-job",
+		"csi-list-volume",
+		"csi-read-volume",
+		"read-job-scaling",
+		"list-scaling-policies",
+		"read-scaling-policy",
+	}
+
+	switch policy {
+	case aclPolicyDeny:
+		return []string{aclPolicyDeny}
+	case aclPolicyRead:
+		return read
+	case aclPolicyWrite:
+		return append(read,
+			"scale-job",
+			"submit-job",
+			"dispatch-job",
+			"read-logs",
+			"read-fs",
+			"alloc-exec",
+			"alloc-lifecycle",
+			"csi-mount-volume",
+			"csi-write-volume",
+			"submit-recommendation",
+		)
+	case aclPolicyScale:
+		return []string{
+			"list-scaling-policies",
+			"read-scaling-policy",
+			"read-job-scaling",
+			"scale-job",
+		}
+	}
+	return nil
+}
+
+// expandNamespaceVariablesPolicy returns the variables rule implied by a
+// namespace policy shorthand.
+func expandNamespaceVariablesPolicy(policy string) *aclVariablesPathRule {
+	switch policy {
+	case aclPolicyRead:
+		return &aclVariablesPathRule{Path: "*", Capabilities: []string{aclPolicyRead, aclPolicyList}}
+	case aclPolicyWrite:
+		return &aclVariablesPathRule{Path: "*", Capabilities: []string{aclPolicyWrite, aclPolicyRead, "destroy", aclPolicyList}}
+	}
+	return nil
+}
+
+// expandVariablesCapabilities adds the list capability implied by read, and
+// collapses the capabilities to deny when it is present.
+func expandVariablesCapabilities(capabilities []string) []string {
+	var foundRead, foundList bool
+	for _, capability := range capabilities {
+		switch capability {
+		case aclPolicyDeny:
+			return []string{aclPolicyDeny}
+		case aclPolicyRead:
+			foundRead = true
+		case aclPolicyList:
+			foundList = true
+		}
+	}
+	if foundRead && !foundList {
+		capabilities = append(capabilities, aclPolicyList)
+	}
+	return capabilities
+}
+
+// expandHostVolumePolicy returns the host volume capabilities granted by a
+// policy shorthand.
+func expandHostVolumePolicy(policy string) []string {
+	switch policy {
+	case aclPolicyDeny:
+		return []string{aclPolicyDeny}
+	case aclPolicyRead:
+		return []string{"mount-readonly"}
+	case aclPolicyWrite:
+		return []string{"mount-readonly", "mount-readwrite"}
+	}
+	return nil
+}
+
+// expandCoarsePolicy returns the capabilities granted by the policy of the
+// agent, node, operator and quota blocks, where write implies read.
+func expandCoarsePolicy(policy string) []string {
+	switch policy {
+	case aclPolicyDeny:
+		return []string{aclPolicyDeny}
+	case aclPolicyRead:
+		return []string{aclPolicyRead}
+	case aclPolicyWrite:
+		return []string{aclPolicyRead, aclPolicyWrite}
+	}
+	return nil
+}
+
+// expandPluginPolicy returns the capabilities granted by the policy of the
+// plugin block, where read implies list.
+func expandPluginPolicy(policy string) []string {
+	switch policy {
+	case aclPolicyDeny:
+		return []string{aclPolicyDeny}
+	case aclPolicyList:
+		return []string{aclPolicyList}
+	case aclPolicyRead:
+		return []string{aclPolicyList, aclPolicyRead}
+	}
+	return nil
+}
+
+// mergeCapabilities returns the union of the capability lists, keeping the
+// order in which they first appear. A deny overrides every other capability.
+func mergeCapabilities(lists ...[]string) []string {
+	seen := map[string]bool{}
+	var merged []string
+	for _, list := range lists {
+		for _, capability := range list {
+			if capability == aclPolicyDeny {
+				return []string{aclPolicyDeny}
+			}
+			if !seen[capability] {
+				seen[capability] = true
+				merged = append(merged, capability)
+			}
+		}
+	}
+	return merged
+}
+
+//// TRANSFORM FUNCTIONS
+
+func parseACLPolicyRulesTransform(ctx context.Context, d *transform.TransformData) (interface{}, error) {
+	policy, ok := d.HydrateItem.(aclPolicyRow)
+	if !ok {
+		return nil, nil
+	}
+
+	parsed, err := parseACLPolicyRules(policy.Rules)
+	if err != nil {
+		plugin.Logger(ctx).Error("nomad_acl_policy.parseACLPolicyRulesTransform", "policy", policy.Name, "parse_error", err)
+		return nil, nil
+	}
+	return parsed, nil
+}
@@ -2,17 +2,36 @@ package nomad
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/nomad/api"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 )
 
 type nomadConfig struct {
-	Address   *string `hcl:"address"`
-	Namespace *string `hcl:"namespace"`
-	SecretID  *string `hcl:"secret_id"`
+	Address        *string           `hcl:"address"`
+	Namespace      *string           `hcl:"namespace"`
+	SecretID       *string           `hcl:"secret_id"`
+	SecretIDFile   *string           `hcl:"secret_id_file"`
+	AuthMethod     *string           `hcl:"auth_method"`
+	LoginTokenFile *string           `hcl:"login_token_file"`
+	HttpAuth       *string           `hcl:"http_auth"`
+	Headers        map[string]string `hcl:"headers,optional"`
+	ProxyURL       *string           `hcl:"proxy_url"`
+	Timeout        *string           `hcl:"timeout"`
+	WaitTime       *string           `hcl:"wait_time"`
+	AllowStale     *bool             `hcl:"allow_stale"`
+	Consistency    *string           `hcl:"consistency"`
+	RevealSecrets  *bool             `hcl:"reveal_secrets"`
 }
 
 func ConfigInstance() interface{} {
@@ -28,30 +47,175 @@ func GetConfig(connection *plugin.Connection) nomadConfig {
 }
 
 func getClient(ctx context.Context, d *plugin.QueryData) (*api.Client, error) {
-	nomadConfig := GetConfig(d.Connection)
+	return newClient(ctx, GetConfig(d.Connection))
+}
 
+// newClient returns a Nomad API client for the connection config.
+func newClient(ctx context.Context, nomadConfig nomadConfig) (*api.Client, error) {
+	con, err := newClientConfig(ctx, nomadConfig)
+	if err != nil {
+		return nil, err
+	}
+	return api.NewClient(con)
+}
+
+// newClientConfig resolves the connection config, falling back on the
+// standard Nomad environment variables, into a Nomad API client config.
+func newClientConfig(ctx context.Context, nomadConfig nomadConfig) (*api.Config, error) {
 	address := os.Getenv("NOMAD_ADDR")
 	namespace := os.Getenv("NOMAD_NAMESPACE")
-	secretId := os.Getenv("NOMAD_TOKEN")
+	httpAuth := os.Getenv("NOMAD_HTTP_AUTH")
 
 	if nomadConfig.Address != nil {
 		address = *nomadConfig.Address
 	}
-	if nomadConfig.SecretID != nil {
-		secretId = *nomadConfig.SecretID
-	}
 	if nomadConfig.Namespace != nil {
 		namespace = *nomadConfig.Namespace
 	}
+	if nomadConfig.HttpAuth != nil {
+		httpAuth = *nomadConfig.HttpAuth
+	}
 
 	if address != "" {
+		httpAddress, socketPath, err := parseAddress(address)
+		if err != nil {
+			return nil, err
+		}
+
 		con := api.DefaultConfig()
-		con.Address = address
-		con.SecretID = secretId
+		con.Address = httpAddress
 		con.Namespace = namespace
-		client, _ := api.NewClient(con)
-		return client, nil
+		con.HttpAuth = parseHttpAuth(httpAuth)
+		if len(nomadConfig.Headers) > 0 {
+			con.Headers = http.Header{}
+			for name, value := range nomadConfig.Headers {
+				con.Headers.Set(name, value)
+			}
+		}
+		if nomadConfig.WaitTime != nil {
+			con.WaitTime, err = parseDuration("wait_time", *nomadConfig.WaitTime)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if nomadConfig.ProxyURL != nil || nomadConfig.Timeout != nil || socketPath != "" {
+			con.HttpClient, err = newHttpClient(nomadConfig, con, socketPath)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err := validateClientConfig(nomadConfig, con); err != nil {
+			return nil, err
+		}
+		secretId, err := getSecretID(ctx, nomadConfig, con)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateSecretID(nomadConfig, secretId); err != nil {
+			return nil, err
+		}
+		con.SecretID = secretId
+		return con, nil
 	}
 
 	return nil, errors.New("'address' or ('address' and 'secret_id') must be set in the connection configuration. Edit your connection configuration file and then restart Steampipe.")
 }
+
+// parseHttpAuth parses HTTP basic auth credentials in the username[:password]
+// format also used by the NOMAD_HTTP_AUTH environment variable.
+func parseHttpAuth(httpAuth string) *api.HttpBasicAuth {
+	if httpAuth == "" {
+		return nil
+	}
+	username, password, _ := strings.Cut(httpAuth, ":")
+	return &api.HttpBasicAuth{
+		Username: username,
+		Password: password,
+	}
+}
+
+// parseAddress validates the address of the Nomad agent. For unix socket
+// addresses, it returns the path of the socket along with a placeholder HTTP
+// address for the Nomad API client to build request URLs from.
+func parseAddress(address string) (string, string, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid 'address' %q in the connection configuration: %v", address, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return "", "", fmt.Errorf("invalid 'address' %q in the connection configuration: missing host, e.g. http://127.0.0.1:4646", address)
+		}
+		return address, "", nil
+	case "unix":
+		socketPath := u.Path
+		if u.Host != "" {
+			socketPath = u.Host + u.Path
+		}
+		if socketPath == "" {
+			return "", "", fmt.Errorf("invalid 'address' %q in the connection configuration: missing socket path, e.g. unix:///var/run/nomad.sock", address)
+		}
+		return "http://localhost", socketPath, nil
+	}
+
+	return "", "", fmt.Errorf("invalid 'address' %q in the connection configuration: must start with http://, https:// or unix://", address)
+}
+
+// parseDuration parses a duration config argument, such as 30s or 1m.
+func parseDuration(name string, value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid '%s' %q in the connection configuration: must be a positive duration, e.g. 30s", name, value)
+	}
+	return duration, nil
+}
+
+// newHttpClient returns an HTTP client honouring the proxy, timeout and unix
+// socket settings of the connection. The Nomad API client ignores its TLS
+// config when given an HTTP client, so the TLS config is applied here.
+func newHttpClient(nomadConfig nomadConfig, con *api.Config, socketPath string) (*http.Client, error) {
+	httpClient := cleanhttp.DefaultPooledClient()
+	transport := httpClient.Transport.(*http.Transport)
+	transport.TLSHandshakeTimeout = 10 * time.Second
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	// The timeout bounds connecting to the agent and waiting for its response
+	// headers, but not reading the response, so that streamed responses such as
+	// allocation logs are not cut short
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if nomadConfig.Timeout != nil {
+		timeout, err := parseDuration("timeout", *nomadConfig.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		dialer.Timeout = timeout
+		transport.TLSHandshakeTimeout = timeout
+		transport.ResponseHeaderTimeout = timeout
+	}
+	transport.DialContext = dialer.DialContext
+	if socketPath != "" {
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socketPath)
+		}
+	}
+
+	if nomadConfig.ProxyURL != nil {
+		proxyURL, err := url.Parse(*nomadConfig.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid 'proxy_url' %q in the connection configuration: %v", *nomadConfig.ProxyURL, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if err := api.ConfigureTLS(httpClient, con.TLSConfig); err != nil {
+		return nil, err
+	}
+	return httpClient, nil
+}
@@ -0,0 +1,127 @@
+package nomad
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+func TestHttpAuth(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/policies", []*api.ACLPolicyListStub{})
+	server := newTestPluginServer(t, f, `http_auth = "steampipe:s3cret"`)
+
+	testQuery{table: "nomad_acl_policy", columns: []string{"name"}}.mustExecute(t, server)
+	for _, r := range f.requestsTo("/v1/acl/policies") {
+		if username, password, ok := r.BasicAuth(); !ok || username != "steampipe" || password != "s3cret" {
+			t.Errorf("got basic auth %q %q, want the http_auth credentials", username, password)
+		}
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/policies", []*api.ACLPolicyListStub{})
+	server := newTestPluginServer(t, f, `headers = { "X-Team" = "platform", "X-Request-Source" = "steampipe" }`)
+
+	testQuery{table: "nomad_acl_policy", columns: []string{"name"}}.mustExecute(t, server)
+	for _, r := range f.requestsTo("/v1/acl/policies") {
+		if r.Header.Get("X-Team") != "platform" || r.Header.Get("X-Request-Source") != "steampipe" {
+			t.Errorf("got headers %v, want the headers of the connection", r.Header)
+		}
+		if r.Header.Get("X-Nomad-Token") != testSecretID {
+			t.Errorf("expected the custom headers not to replace the token")
+		}
+	}
+}
+
+func TestProxyURL(t *testing.T) {
+	// The fake server acts as the proxy of an agent that cannot be resolved,
+	// so requests only succeed if they are sent through the proxy
+	proxy := newFakeNomad(t)
+	proxy.handle("/v1/acl/policies", []*api.ACLPolicyListStub{})
+	server := newTestPluginServerWithConfig(t, fmt.Sprintf("address = %q\nsecret_id = %q\nproxy_url = %q", "http://nomad.invalid:4646", testSecretID, proxy.URL))
+
+	testQuery{table: "nomad_acl_policy", columns: []string{"name"}}.mustExecute(t, server)
+	requests := proxy.requestsTo("/v1/acl/policies")
+	if len(requests) == 0 {
+		t.Fatalf("expected the request to be sent through the proxy")
+	}
+	for _, r := range requests {
+		if r.Host != "nomad.invalid:4646" || r.URL.Host != "nomad.invalid:4646" {
+			t.Errorf("got a proxied request for %q, want the address of the agent", r.URL)
+		}
+	}
+}
+
+func TestUnixSocketAddress(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/policies", []*api.ACLPolicyListStub{{Name: "readonly"}})
+
+	// Socket paths are limited to about 100 bytes, so the socket is not created
+	// in the longer test directory
+	dir, err := os.MkdirTemp("", "nomad")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socketPath := filepath.Join(dir, "nomad.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	socketServer := &http.Server{Handler: http.HandlerFunc(f.serveHTTP)}
+	go socketServer.Serve(listener)
+	t.Cleanup(func() { socketServer.Close() })
+
+	server := newTestPluginServerWithConfig(t, fmt.Sprintf("address = %q\nsecret_id = %q", "unix://"+socketPath, testSecretID))
+	rows := testQuery{table: "nomad_acl_policy", columns: []string{"name"}}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].string("name") != "readonly" {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/policies", []*api.ACLPolicyListStub{})
+
+	// The agent takes longer than the timeout to respond to the list request
+	release := make(chan struct{})
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/acl/policies" {
+			<-release
+		}
+		f.serveHTTP(w, r)
+	})
+	f.Config.Handler = slow
+	defer close(release)
+
+	server := newTestPluginServer(t, f, `timeout = "200ms"`)
+	start := time.Now()
+	_, err := testQuery{table: "nomad_acl_policy", columns: []string{"name"}}.execute(t, server)
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("got error %v, want the request to time out", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("query took %s, want it to fail once the timeout elapsed", elapsed)
+	}
+}
+
+func TestWaitTime(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/policies", []*api.ACLPolicyListStub{})
+	server := newTestPluginServer(t, f, `wait_time = "5m"`)
+
+	testQuery{table: "nomad_acl_policy", columns: []string{"name"}}.mustExecute(t, server)
+	requests := f.requestsTo("/v1/acl/policies")
+	if len(requests) == 0 || requests[0].URL.Query().Get("wait") != "300000ms" {
+		t.Errorf("expected the wait time to be sent with the requests")
+	}
+}
@@ -0,0 +1,99 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+// validSecretID matches the UUID format of ACL token secret IDs
+var validSecretID = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+
+// tableMapWithValidation validates the connection before returning the tables
+// of the plugin, so that a misconfigured connection fails to load with an
+// actionable error instead of failing every query. The plugin uses a dynamic
+// schema mode for this to run for every connection, but the tables do not
+// depend on the connection.
+func tableMapWithValidation(tableMap map[string]*plugin.Table) plugin.TableMapFunc {
+	return func(ctx context.Context, d *plugin.TableMapData) (map[string]*plugin.Table, error) {
+		if err := validateConnection(ctx, d.Connection); err != nil {
+			plugin.Logger(ctx).Error("nomad.validateConnection", "connection", d.Connection.Name, "validation_error", err)
+			return nil, fmt.Errorf("connection %s: %v", d.Connection.Name, err)
+		}
+		return tableMap, nil
+	}
+}
+
+// validateConnection checks the connection config and that the Nomad agent can
+// be reached with it.
+func validateConnection(ctx context.Context, connection *plugin.Connection) error {
+	con, err := newClientConfig(ctx, GetConfig(connection))
+	if err != nil {
+		return err
+	}
+
+	client, err := api.NewClient(con)
+	if err != nil {
+		return err
+	}
+	if _, err := client.Status().Leader(); err != nil {
+		return fmt.Errorf("failed to reach the Nomad agent at %s: %v. Check the 'address' argument of the connection, or the NOMAD_ADDR environment variable, and that the agent is running", con.Address, err)
+	}
+
+	return nil
+}
+
+// validateClientConfig checks the connection config before any request is made
+// to the Nomad agent, so that a misconfigured connection fails with an
+// actionable error.
+func validateClientConfig(nomadConfig nomadConfig, con *api.Config) error {
+	if _, err := nomadConfig.consistency(); err != nil {
+		return err
+	}
+	return validateTLSConfig(con.TLSConfig)
+}
+
+// validateSecretID checks that the secret ID set in the connection config or
+// the environment is shaped like the secret ID of an ACL token. Tokens obtained
+// through an auth method login are issued by Nomad, so they are not checked.
+func validateSecretID(nomadConfig nomadConfig, secretID string) error {
+	if secretID != "" && nomadConfig.AuthMethod == nil && !validSecretID.MatchString(secretID) {
+		return fmt.Errorf("invalid ACL token secret ID: must be a UUID, e.g. c178b810-8b18-6f38-016f-725ddec5d58b. Check the 'secret_id' or 'secret_id_file' arguments of the connection, or the NOMAD_TOKEN environment variable, and that the secret ID rather than the accessor ID of the token is used")
+	}
+	return nil
+}
+
+// validateTLSConfig checks that the certificate files set through the standard
+// Nomad environment variables exist.
+func validateTLSConfig(tlsConfig *api.TLSConfig) error {
+	if tlsConfig == nil {
+		return nil
+	}
+
+	for _, file := range []struct {
+		path string
+		env  string
+	}{
+		{tlsConfig.CACert, "NOMAD_CACERT"},
+		{tlsConfig.CAPath, "NOMAD_CAPATH"},
+		{tlsConfig.ClientCert, "NOMAD_CLIENT_CERT"},
+		{tlsConfig.ClientKey, "NOMAD_CLIENT_KEY"},
+	} {
+		if file.path == "" {
+			continue
+		}
+		if _, err := os.Stat(file.path); err != nil {
+			return fmt.Errorf("invalid %s environment variable: %v", file.env, err)
+		}
+	}
+
+	if (tlsConfig.ClientCert == "") != (tlsConfig.ClientKey == "") {
+		return fmt.Errorf("NOMAD_CLIENT_CERT and NOMAD_CLIENT_KEY environment variables must be set together")
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+func TestConnectionValidation(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/policies", []*api.ACLPolicyListStub{})
+	unreachable := newFakeNomad(t)
+	unreachable.handleError("/v1/status/leader", http.StatusInternalServerError, "No cluster leader")
+
+	for name, tc := range map[string]struct {
+		config string
+		want   string
+	}{
+		"valid":               {config: fmt.Sprintf("address = %q\nsecret_id = %q", f.URL, testSecretID)},
+		"invalid address":     {config: `address = "ftp://nomad"`, want: "address"},
+		"invalid secret ID":   {config: fmt.Sprintf("address = %q\nsecret_id = %q", f.URL, "not-a-uuid"), want: "must be a UUID"},
+		"invalid consistency": {config: fmt.Sprintf("address = %q\nconsistency = %q", f.URL, "consistent"), want: "invalid 'consistency'"},
+		"unreachable agent":   {config: fmt.Sprintf("address = %q", unreachable.URL), want: "failed to reach the Nomad agent"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("NOMAD_TOKEN", "")
+
+			server := plugin.Server(&plugin.ServeOpts{PluginFunc: Plugin})
+			res, err := server.SetAllConnectionConfigs(&proto.SetAllConnectionConfigsRequest{
+				Configs: []*proto.ConnectionConfig{{Connection: "nomad", Plugin: "nomad", Config: tc.config}},
+			})
+			// The load error is returned, or reported as a failed connection
+			// when other connections load
+			failure := res.GetFailedConnections()["nomad"]
+			if err != nil {
+				failure = err.Error()
+			}
+			if tc.want == "" {
+				if failure != "" {
+					t.Fatalf("got load error %q, want the connection to load", failure)
+				}
+				if _, err := (testQuery{table: "nomad_acl_policy", columns: []string{"name"}}).execute(t, server); err != nil {
+					t.Errorf("got error %v, want the query to succeed", err)
+				}
+				return
+			}
+			if !strings.Contains(failure, tc.want) {
+				t.Errorf("got load error %q, want it to mention %q", failure, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/query_cache"
+)
+
+// Consistency modes of the reads. Reads are served by the leader by default,
+// while stale reads can be served by any server, at the cost of possibly
+// lagging behind the leader.
+const (
+	consistencyDefault = "default"
+	consistencyStale   = "stale"
+)
+
+// consistency returns the consistency mode of the reads of the connection.
+// allow_stale is a shorthand for the stale consistency mode.
+func (c nomadConfig) consistency() (string, error) {
+	consistency := consistencyDefault
+	if c.AllowStale != nil && *c.AllowStale {
+		consistency = consistencyStale
+	}
+	if c.Consistency == nil {
+		return consistency, nil
+	}
+
+	switch *c.Consistency {
+	case consistencyDefault, consistencyStale:
+	default:
+		return "", fmt.Errorf("invalid 'consistency' %q in the connection configuration: must be %q or %q", *c.Consistency, consistencyDefault, consistencyStale)
+	}
+	if c.AllowStale != nil && *c.AllowStale != (*c.Consistency == consistencyStale) {
+		return "", fmt.Errorf("conflicting 'allow_stale' and 'consistency' in the connection configuration: set only one of them")
+	}
+	return *c.Consistency, nil
+}
+
+// queryConsistency returns the consistency mode of the reads of the query,
+// set by the consistency qual or else by the connection. Unknown modes in the
+// qual are ignored, so that the rows do not match the qual.
+func queryConsistency(d *plugin.QueryData) string {
+	switch consistency := d.EqualsQualString("consistency"); consistency {
+	case consistencyDefault, consistencyStale:
+		return consistency
+	}
+
+	// The connection config is validated when the client of the query is created
+	consistency, err := GetConfig(d.Connection).consistency()
+	if err != nil {
+		return consistencyDefault
+	}
+	return consistency
+}
+
+// queryOptions returns the options of the read requests of the query.
+func queryOptions(d *plugin.QueryData) *api.QueryOptions {
+	return &api.QueryOptions{
+		AllowStale: queryConsistency(d) == consistencyStale,
+	}
+}
+
+// consistencyKeyColumn lets a query set the consistency mode of its reads.
+// Cached rows are only reused by queries with the same consistency qual, as
+// they hold the consistency mode they were read with.
+func consistencyKeyColumn() *plugin.KeyColumn {
+	return &plugin.KeyColumn{
+		Name:       "consistency",
+		Require:    plugin.Optional,
+		CacheMatch: query_cache.CacheMatchExact,
+	}
+}
+
+// queryMetaColumns returns the columns of a table along with the columns
+// describing the consistency of the response each row was read from, inserted
+// before the Steampipe standard columns.
+func queryMetaColumns(columns []*plugin.Column) []*plugin.Column {
+	metaColumns := []*plugin.Column{
+		{
+			Name:        "consistency",
+			Type:        proto.ColumnType_STRING,
+			Description: "The consistency mode the row was read with, either default (served by the leader) or stale (served by any server). Can be set per query, or else by the connection.",
+			Hydrate:     getQueryMeta,
+		},
+		{
+			Name:        "last_contact",
+			Type:        proto.ColumnType_INT,
+			Description: "The time in milliseconds since the server serving the row last contacted the leader. Always 0 for reads served by the leader.",
+			Hydrate:     getQueryMeta,
+		},
+		{
+			Name:        "last_index",
+			Type:        proto.ColumnType_INT,
+			Description: "The Raft index of the data the row was read from.",
+			Hydrate:     getQueryMeta,
+		},
+		{
+			Name:        "known_leader",
+			Type:        proto.ColumnType_BOOL,
+			Description: "Whether the server serving the row knew of a cluster leader.",
+			Hydrate:     getQueryMeta,
+		},
+	}
+
+	standard := len(columns)
+	for i, column := range columns {
+		if column.Name == "title" || column.Name == "tags" || column.Name == "akas" {
+			standard = i
+			break
+		}
+	}
+
+	result := make([]*plugin.Column, 0, len(columns)+len(metaColumns))
+	result = append(result, columns[:standard]...)
+	result = append(result, metaColumns...)
+	return append(result, columns[standard:]...)
+}
+
+type queryMetaInfo struct {
+	Consistency string
+	LastContact *int64
+	LastIndex   *uint64
+	KnownLeader *bool
+}
+
+// queryMetaRow is embedded in the rows of the tables with the query meta
+// columns, to carry the metadata of the response each row was read from. The
+// rows embed the Nomad API object by value, so that the transforms find its
+// fields: the <resource>ListRow types hold the stubs returned by list calls,
+// along with the metadata of their page, and the <resource>Row types the
+// objects returned by get calls.
+type queryMetaRow struct {
+	meta *api.QueryMeta
+}
+
+func (r queryMetaRow) queryMeta() *api.QueryMeta {
+	return r.meta
+}
+
+// queryMetaItem is implemented by the rows embedding queryMetaRow.
+type queryMetaItem interface {
+	queryMeta() *api.QueryMeta
+}
+
+func getQueryMeta(_ context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	info := queryMetaInfo{
+		Consistency: queryConsistency(d),
+	}
+
+	row, ok := h.Item.(queryMetaItem)
+	if !ok || row.queryMeta() == nil {
+		return info, nil
+	}
+
+	meta := row.queryMeta()
+	info.LastContact = pointerOf(meta.LastContact.Milliseconds())
+	info.LastIndex = pointerOf(meta.LastIndex)
+	info.KnownLeader = pointerOf(meta.KnownLeader)
+	return info, nil
+}
@@ -0,0 +1,124 @@
+package nomad
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func TestConnectionConsistency(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config nomadConfig
+		want   string
+		err    string
+	}{
+		"unset":                   {want: consistencyDefault},
+		"allow stale":             {config: nomadConfig{AllowStale: pointerOf(true)}, want: consistencyStale},
+		"disallow stale":          {config: nomadConfig{AllowStale: pointerOf(false)}, want: consistencyDefault},
+		"stale":                   {config: nomadConfig{Consistency: pointerOf("stale")}, want: consistencyStale},
+		"default":                 {config: nomadConfig{Consistency: pointerOf("default")}, want: consistencyDefault},
+		"matching allow stale":    {config: nomadConfig{AllowStale: pointerOf(true), Consistency: pointerOf("stale")}, want: consistencyStale},
+		"invalid":                 {config: nomadConfig{Consistency: pointerOf("consistent")}, err: "invalid 'consistency'"},
+		"conflicting allow stale": {config: nomadConfig{AllowStale: pointerOf(true), Consistency: pointerOf("default")}, err: "conflicting"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.config.consistency()
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Errorf("got error %v, want it to mention %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("got consistency %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+var queryMetaTestColumns = []string{"id", "consistency", "last_contact", "last_index", "known_leader"}
+
+func TestStaleReadsFromConnection(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handlePages("/v1/jobs",
+		[]*api.JobListStub{{ID: "batch"}},
+		[]*api.JobListStub{{ID: "web"}},
+	)
+	server := newTestPluginServer(t, f, "allow_stale = true")
+
+	rows := testQuery{table: "nomad_job", columns: queryMetaTestColumns}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	for _, row := range rows {
+		if row.string("consistency") != consistencyStale || row.int("last_contact") != testLastContact || row.int("last_index") != 1 || !row.bool("known_leader") {
+			t.Errorf("unexpected query meta of row %s: %v", row.string("id"), row)
+		}
+	}
+
+	// Every page is read with the consistency of the connection
+	requests := f.requestsTo("/v1/jobs")
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	for _, request := range requests {
+		if !request.URL.Query().Has("stale") {
+			t.Errorf("got request %s, want a stale read", request.URL)
+		}
+	}
+}
+
+func TestConsistencyQual(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/nodes", []*api.NodeListStub{{ID: "node-1", Name: "client"}})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{table: "nomad_node", columns: queryMetaTestColumns}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].string("consistency") != consistencyDefault || rows[0].int("last_contact") != 0 {
+		t.Errorf("got rows %v, want a default read", rows)
+	}
+
+	// The qual overrides the consistency of the connection
+	rows = testQuery{
+		table:   "nomad_node",
+		columns: queryMetaTestColumns,
+		quals:   equalsQuals(map[string]*proto.QualValue{"consistency": stringQual("stale")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].string("consistency") != consistencyStale || rows[0].int("last_contact") != testLastContact {
+		t.Errorf("got rows %v, want a stale read", rows)
+	}
+
+	requests := f.requestsTo("/v1/nodes")
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	if requests[0].URL.Query().Has("stale") || !requests[1].URL.Query().Has("stale") {
+		t.Errorf("got requests %s and %s, want only the second to be a stale read", requests[0].URL, requests[1].URL)
+	}
+}
+
+func TestGetQueryMeta(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/job/web", &api.Job{ID: pointerOf("web"), Name: pointerOf("web")})
+	server := newTestPluginServer(t, f, `consistency = "stale"`)
+
+	rows := testQuery{
+		table:   "nomad_job",
+		columns: queryMetaTestColumns,
+		quals:   equalsQuals(map[string]*proto.QualValue{"id": stringQual("web")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(rows))
+	}
+	if rows[0].string("consistency") != consistencyStale || rows[0].int("last_contact") != testLastContact || rows[0].int("last_index") != 1 {
+		t.Errorf("unexpected query meta of the job: %v", rows[0])
+	}
+	if requests := f.requestsTo("/v1/job/web"); len(requests) != 1 || !requests[0].URL.Query().Has("stale") {
+		t.Errorf("want a single stale read of the job")
+	}
+}
@@ -0,0 +1,128 @@
+package nomad
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+// loginTokenExpiryMargin is how long before its expiration a token obtained
+// through an auth method login is renewed
+const loginTokenExpiryMargin = time.Minute
+
+type cachedFile struct {
+	modTime time.Time
+	size    int64
+	content string
+}
+
+var (
+	cachedFilesMutex sync.Mutex
+	cachedFiles      = map[string]cachedFile{}
+
+	loginTokensMutex sync.Mutex
+	loginTokens      = map[string]*api.ACLToken{}
+)
+
+// getSecretID returns the secret ID of the ACL token to query Nomad with, in
+// order of precedence from the secret_id, secret_id_file and auth_method
+// config arguments, or the NOMAD_TOKEN environment variable.
+func getSecretID(ctx context.Context, nomadConfig nomadConfig, con *api.Config) (string, error) {
+	switch {
+	case nomadConfig.SecretID != nil:
+		return *nomadConfig.SecretID, nil
+	case nomadConfig.SecretIDFile != nil:
+		return readCachedFile(*nomadConfig.SecretIDFile)
+	case nomadConfig.AuthMethod != nil:
+		return loginSecretID(ctx, nomadConfig, con)
+	}
+	return os.Getenv("NOMAD_TOKEN"), nil
+}
+
+// readCachedFile returns the trimmed content of a file, only reading it again
+// if it has changed since it was last read.
+func readCachedFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	cachedFilesMutex.Lock()
+	defer cachedFilesMutex.Unlock()
+
+	if cached, ok := cachedFiles[path]; ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.content, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	cachedFiles[path] = cachedFile{
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		content: strings.TrimSpace(string(content)),
+	}
+	return cachedFiles[path].content, nil
+}
+
+// loginSecretID exchanges the login token for a Nomad ACL token through the
+// configured auth method. The ACL token is cached and reused until shortly
+// before it expires, or until the login token changes.
+func loginSecretID(ctx context.Context, nomadConfig nomadConfig, con *api.Config) (string, error) {
+	loginToken := os.Getenv("NOMAD_LOGIN_TOKEN")
+	if nomadConfig.LoginTokenFile != nil {
+		var err error
+		loginToken, err = readCachedFile(*nomadConfig.LoginTokenFile)
+		if err != nil {
+			return "", err
+		}
+	}
+	if loginToken == "" {
+		return "", errors.New("'login_token_file' or the NOMAD_LOGIN_TOKEN environment variable must be set when 'auth_method' is set in the connection configuration. Edit your connection configuration file and then restart Steampipe.")
+	}
+
+	sum := sha256.Sum256([]byte(loginToken))
+	key := con.Address + "/" + *nomadConfig.AuthMethod + "/" + hex.EncodeToString(sum[:])
+
+	// The lock is only held around the cache, so that a slow login does not
+	// block the other connections. Concurrent queries may then log in more than
+	// once, and the last token obtained is kept.
+	loginTokensMutex.Lock()
+	token, ok := loginTokens[key]
+	loginTokensMutex.Unlock()
+	if ok && (token.ExpirationTime == nil || time.Now().Add(loginTokenExpiryMargin).Before(*token.ExpirationTime)) {
+		return token.SecretID, nil
+	}
+
+	// The login endpoint does not need a token, so do not send the one that may
+	// have been picked up from the environment
+	loginCon := *con
+	loginCon.SecretID = ""
+	client, err := api.NewClient(&loginCon)
+	if err != nil {
+		return "", err
+	}
+	token, _, err = client.ACLAuth().Login(&api.ACLLoginRequest{
+		AuthMethodName: *nomadConfig.AuthMethod,
+		LoginToken:     loginToken,
+	}, nil)
+	if err != nil {
+		plugin.Logger(ctx).Error("nomad.loginSecretID", "auth_method", *nomadConfig.AuthMethod, "api_error", err)
+		return "", fmt.Errorf("failed to login with auth method %q: %v", *nomadConfig.AuthMethod, err)
+	}
+	loginTokensMutex.Lock()
+	loginTokens[key] = token
+	loginTokensMutex.Unlock()
+
+	return token.SecretID, nil
+}
@@ -0,0 +1,153 @@
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+const (
+	testLoginSecretID = "5a9c7d5e-2f4b-4e0a-9d6f-3b8e1c2a7f40"
+	testOtherSecretID = "0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0"
+)
+
+// writeTestFile writes content to the file at path with the given
+// modification time, so that changes are noticed whatever the resolution of
+// the file system clock.
+func writeTestFile(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// lastToken returns the ACL token sent with the last request to path.
+func lastToken(t *testing.T, f *fakeNomad, path string) string {
+	t.Helper()
+	requests := f.requestsTo(path)
+	if len(requests) == 0 {
+		t.Fatalf("no request to %s", path)
+	}
+	return requests[len(requests)-1].Header.Get("X-Nomad-Token")
+}
+
+func TestSecretIDFileReread(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/policies", []*api.ACLPolicyListStub{})
+	path := filepath.Join(t.TempDir(), "token")
+	modTime := time.Now().Add(-time.Hour)
+	writeTestFile(t, path, testSecretID+"\n", modTime)
+	server := newTestPluginServerWithConfig(t, fmt.Sprintf("address = %q\nsecret_id_file = %q", f.URL, path))
+
+	testQuery{table: "nomad_acl_policy", columns: []string{"name"}}.mustExecute(t, server)
+	if token := lastToken(t, f, "/v1/acl/policies"); token != testSecretID {
+		t.Errorf("got token %q, want the content of the file", token)
+	}
+
+	// A rotated token is picked up by the next query
+	writeTestFile(t, path, testOtherSecretID, modTime.Add(time.Minute))
+	testQuery{table: "nomad_acl_policy", columns: []string{"name", "description"}}.mustExecute(t, server)
+	if token := lastToken(t, f, "/v1/acl/policies"); token != testOtherSecretID {
+		t.Errorf("got token %q, want the rotated token", token)
+	}
+}
+
+func TestAuthMethodLogin(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/policies", []*api.ACLPolicyListStub{})
+	expiration := time.Now().Add(time.Hour)
+	f.handle("/v1/acl/login", &api.ACLToken{SecretID: testLoginSecretID, ExpirationTime: &expiration})
+	path := filepath.Join(t.TempDir(), "jwt")
+	writeTestFile(t, path, "login-token", time.Now())
+	server := newTestPluginServerWithConfig(t, fmt.Sprintf("address = %q\nauth_method = %q\nlogin_token_file = %q", f.URL, "github", path))
+
+	testQuery{table: "nomad_acl_policy", columns: []string{"name"}}.mustExecute(t, server)
+	testQuery{table: "nomad_acl_policy", columns: []string{"name", "description"}}.mustExecute(t, server)
+
+	// The token obtained when the connection loaded is reused by the queries
+	logins := f.requestsTo("/v1/acl/login")
+	if len(logins) != 1 {
+		t.Fatalf("got %d logins, want the login token to be exchanged once", len(logins))
+	}
+	if logins[0].Header.Get("X-Nomad-Token") != "" {
+		t.Errorf("expected the login request not to send a token")
+	}
+	for _, r := range f.requestsTo("/v1/acl/policies") {
+		if token := r.Header.Get("X-Nomad-Token"); token != testLoginSecretID {
+			t.Errorf("got token %q, want the token obtained by the login", token)
+		}
+	}
+}
+
+func TestAuthMethodLoginExpiry(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/policies", []*api.ACLPolicyListStub{})
+	// The token expires within the renewal margin, so it is never reused
+	expiration := time.Now().Add(loginTokenExpiryMargin / 2)
+	f.handle("/v1/acl/login", &api.ACLToken{SecretID: testLoginSecretID, ExpirationTime: &expiration})
+	path := filepath.Join(t.TempDir(), "jwt")
+	writeTestFile(t, path, "login-token", time.Now())
+	server := newTestPluginServerWithConfig(t, fmt.Sprintf("address = %q\nauth_method = %q\nlogin_token_file = %q", f.URL, "github", path))
+
+	logins := len(f.requestsTo("/v1/acl/login"))
+	testQuery{table: "nomad_acl_policy", columns: []string{"name"}}.mustExecute(t, server)
+	if got := len(f.requestsTo("/v1/acl/login")); got != logins+1 {
+		t.Errorf("got %d logins, want the query to log in again", got-logins)
+	}
+}
+
+func TestAuthMethodLoginDoesNotBlock(t *testing.T) {
+	// The login on the slow server hangs until released
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		fmt.Fprintf(w, `{"SecretID": %q}`, testOtherSecretID)
+	}))
+	t.Cleanup(slow.Close)
+
+	fast := newFakeNomad(t)
+	fast.handle("/v1/acl/login", &api.ACLToken{SecretID: testLoginSecretID})
+
+	t.Setenv("NOMAD_LOGIN_TOKEN", "login-token")
+	slowDone := make(chan struct{})
+	go func() {
+		defer close(slowDone)
+		loginSecretID(t.Context(), nomadConfig{AuthMethod: pointerOf("github")}, &api.Config{Address: slow.URL})
+	}()
+	defer func() {
+		close(release)
+		<-slowDone
+	}()
+
+	// Wait for the slow login to be in flight before logging in elsewhere
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the slow login was not sent")
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := loginSecretID(t.Context(), nomadConfig{AuthMethod: pointerOf("github")}, &api.Config{Address: fast.URL})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("login failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected the login not to wait for the login on another server")
+	}
+}
@@ -32,3 +32,17 @@ func shouldRetryError(retryErrors []string) plugin.ErrorPredicateWithContext {
 		return false
 	}
 }
+
+// isNotFoundError returns true if the Nomad API responded with a 404
+func isNotFoundError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "404")
+}
+
+// enterpriseOnlyIgnoreConfig ignores the errors of the endpoints only served by
+// Nomad Enterprise, which other clusters answer with a 404 or a 501, so that
+// the tables of Enterprise features are empty on those clusters.
+func enterpriseOnlyIgnoreConfig() *plugin.IgnoreConfig {
+	return &plugin.IgnoreConfig{
+		ShouldIgnoreErrorFunc: shouldIgnoreErrors([]string{"404", "501", "Nomad Enterprise only"}),
+	}
+}
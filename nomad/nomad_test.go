@@ -0,0 +1,296 @@
+package nomad
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	googlegrpc "google.golang.org/grpc"
+)
+
+// testSecretID is the secret ID the test connection is configured with
+const testSecretID = "c178b810-8b18-6f38-016f-725ddec5d58b"
+
+// testLastContact is the time in milliseconds since the follower serving the
+// stale reads of the fake Nomad server last contacted the leader
+const testLastContact = 120
+
+// fakeNomad is an in-process Nomad HTTP API serving canned responses.
+type fakeNomad struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	routes   map[string]fakeRoute
+	requests []*http.Request
+}
+
+type fakeRoute struct {
+	status int
+	raw    bool
+	open   bool
+	pages  []interface{}
+}
+
+func newFakeNomad(t *testing.T) *fakeNomad {
+	t.Helper()
+	f := &fakeNomad{routes: map[string]fakeRoute{}}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.Close)
+
+	// The leader is queried when the connection is loaded
+	f.handle("/v1/status/leader", "10.0.0.1:4647")
+	return f
+}
+
+// handle serves the JSON encoding of body on path.
+func (f *fakeNomad) handle(path string, body interface{}) {
+	f.handlePages(path, body)
+}
+
+// handlePages serves each page on path in turn, linking them through the
+// X-Nomad-NextToken header and the next_token query parameter, the same way
+// Nomad paginates list endpoints.
+func (f *fakeNomad) handlePages(path string, pages ...interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.routes[path] = fakeRoute{status: http.StatusOK, pages: pages}
+}
+
+// handleRaw serves the body as is on path, for the endpoints streaming
+// content rather than returning JSON.
+func (f *fakeNomad) handleRaw(path string, body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.routes[path] = fakeRoute{status: http.StatusOK, raw: true, pages: []interface{}{body}}
+}
+
+// handleStream serves the body as is on path and keeps the response open until
+// the client goes away, the same way Nomad serves streaming endpoints.
+func (f *fakeNomad) handleStream(path string, body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.routes[path] = fakeRoute{status: http.StatusOK, raw: true, open: true, pages: []interface{}{body}}
+}
+
+// handleError responds to requests on path with the status code and message.
+func (f *fakeNomad) handleError(path string, status int, message string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.routes[path] = fakeRoute{status: status, pages: []interface{}{message}}
+}
+
+// requestsTo returns the requests received on path.
+func (f *fakeNomad) requestsTo(path string) []*http.Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var requests []*http.Request
+	for _, r := range f.requests {
+		if r.URL.Path == path {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+func (f *fakeNomad) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests = append(f.requests, r)
+	route, ok := f.routes[r.URL.Path]
+	f.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "resource not found", http.StatusNotFound)
+		return
+	}
+	if route.status != http.StatusOK {
+		http.Error(w, route.pages[0].(string), route.status)
+		return
+	}
+	if route.raw {
+		fmt.Fprint(w, route.pages[0])
+		if route.open {
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+		return
+	}
+
+	page := 0
+	if token := r.URL.Query().Get("next_token"); token != "" {
+		var err error
+		if page, err = strconv.Atoi(token); err != nil || page >= len(route.pages) {
+			http.Error(w, "invalid next_token", http.StatusBadRequest)
+			return
+		}
+	}
+	if page+1 < len(route.pages) {
+		w.Header().Set("X-Nomad-NextToken", strconv.Itoa(page+1))
+	}
+	// Stale reads may be served by a follower, lagging behind the leader
+	lastContact := "0"
+	if r.URL.Query().Has("stale") {
+		lastContact = strconv.Itoa(testLastContact)
+	}
+	w.Header().Set("X-Nomad-Index", "1")
+	w.Header().Set("X-Nomad-LastContact", lastContact)
+	w.Header().Set("X-Nomad-KnownLeader", "true")
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(route.pages[page]); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// newTestPluginServer loads the plugin with a single connection to the fake
+// Nomad server.
+func newTestPluginServer(t *testing.T, f *fakeNomad, extraConfig string) *grpc.PluginServer {
+	t.Helper()
+	return newTestPluginServerWithConfig(t, fmt.Sprintf("address = %q\nsecret_id = %q\n%s", f.URL, testSecretID, extraConfig))
+}
+
+// newTestPluginServerWithConfig loads the plugin with a single connection
+// configured with config.
+func newTestPluginServerWithConfig(t *testing.T, config string) *grpc.PluginServer {
+	t.Helper()
+	t.Setenv("NOMAD_ADDR", "")
+	t.Setenv("NOMAD_TOKEN", "")
+	t.Setenv("NOMAD_NAMESPACE", "")
+
+	server := plugin.Server(&plugin.ServeOpts{PluginFunc: Plugin})
+	res, err := server.SetAllConnectionConfigs(&proto.SetAllConnectionConfigsRequest{
+		Configs: []*proto.ConnectionConfig{
+			{Connection: "nomad", Plugin: "nomad", Config: config},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to load the connection: %v", err)
+	}
+	if len(res.FailedConnections) > 0 {
+		t.Fatalf("failed to load the connection: %v", res.FailedConnections)
+	}
+	return server
+}
+
+// testQuery describes a query against a table of the plugin.
+type testQuery struct {
+	table   string
+	columns []string
+	quals   map[string]*proto.Quals
+	limit   int64
+}
+
+// testRow maps column names to their values.
+type testRow map[string]*proto.Column
+
+func (r testRow) string(column string) string {
+	return r[column].GetStringValue()
+}
+
+func (r testRow) int(column string) int64 {
+	return r[column].GetIntValue()
+}
+
+func (r testRow) bool(column string) bool {
+	return r[column].GetBoolValue()
+}
+
+func (r testRow) json(column string) string {
+	return string(r[column].GetJsonValue())
+}
+
+func (r testRow) isNull(column string) bool {
+	_, ok := r[column].GetValue().(*proto.Column_NullValue)
+	return ok
+}
+
+// execute runs the query and returns the rows streamed by the plugin.
+func (q testQuery) execute(t *testing.T, server *grpc.PluginServer) ([]testRow, error) {
+	t.Helper()
+	queryContext := &proto.QueryContext{
+		Columns: q.columns,
+		Quals:   q.quals,
+	}
+	executeData := &proto.ExecuteConnectionData{}
+	if q.limit > 0 {
+		queryContext.Limit = &proto.NullableInt{Value: q.limit}
+		executeData.Limit = queryContext.Limit
+	}
+	stream := &testExecuteStream{ctx: context.Background()}
+	err := server.Execute(&proto.ExecuteRequest{
+		Table:                 q.table,
+		QueryContext:          queryContext,
+		Connection:            "nomad",
+		CallId:                t.Name(),
+		ExecuteConnectionData: map[string]*proto.ExecuteConnectionData{"nomad": executeData},
+	}, stream)
+	return stream.rows, err
+}
+
+// mustExecute runs the query and fails the test on error.
+func (q testQuery) mustExecute(t *testing.T, server *grpc.PluginServer) []testRow {
+	t.Helper()
+	rows, err := q.execute(t, server)
+	if err != nil {
+		t.Fatalf("query on %s failed: %v", q.table, err)
+	}
+	return rows
+}
+
+// equalsQuals returns the quals of a query with each column equal to a value.
+func equalsQuals(values map[string]*proto.QualValue) map[string]*proto.Quals {
+	quals := map[string]*proto.Quals{}
+	for column, value := range values {
+		quals[column] = &proto.Quals{Quals: []*proto.Qual{newQual(column, "=", value)}}
+	}
+	return quals
+}
+
+func newQual(column string, operator string, value *proto.QualValue) *proto.Qual {
+	return &proto.Qual{
+		FieldName: column,
+		Operator:  &proto.Qual_StringValue{StringValue: operator},
+		Value:     value,
+	}
+}
+
+func stringQual(value string) *proto.QualValue {
+	return &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: value}}
+}
+
+func int64Qual(value int64) *proto.QualValue {
+	return &proto.QualValue{Value: &proto.QualValue_Int64Value{Int64Value: value}}
+}
+
+func jsonQual(value string) *proto.QualValue {
+	return &proto.QualValue{Value: &proto.QualValue_JsonbValue{JsonbValue: value}}
+}
+
+// testExecuteStream collects the rows streamed by an execute call.
+type testExecuteStream struct {
+	googlegrpc.ServerStream
+
+	ctx  context.Context
+	mu   sync.Mutex
+	rows []testRow
+}
+
+func (s *testExecuteStream) Send(res *proto.ExecuteResponse) error {
+	if res.Row == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rows = append(s.rows, res.Row.Columns)
+	return nil
+}
+
+func (s *testExecuteStream) Context() context.Context {
+	return s.ctx
+}
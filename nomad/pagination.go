@@ -0,0 +1,118 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+// defaultPageSize is the number of items requested per page of a list call
+// when the SQL LIMIT cannot be used as the page size
+const defaultPageSize = int32(1000)
+
+// listFunc requests a page of items from a list endpoint of the Nomad API.
+type listFunc[T any] func(*api.QueryOptions) ([]T, *api.QueryMeta, error)
+
+// streamPages streams a row for every item returned by a list endpoint, until
+// the last page or the limit of the query is reached. name prefixes the log
+// messages and resource names the listed items in errors. row builds the row
+// of an item from the item and the metadata of the page it was listed from.
+func streamPages[T any](ctx context.Context, d *plugin.QueryData, name, resource string, input *api.QueryOptions, list listFunc[T], row func(T, *api.QueryMeta) interface{}) error {
+	var meta *api.QueryMeta
+	listPage := func(input *api.QueryOptions) ([]T, *api.QueryMeta, error) {
+		items, metadata, err := list(input)
+		meta = metadata
+		return items, metadata, err
+	}
+
+	return listPages(ctx, d, name, resource, input, listPage, func(item T) (bool, error) {
+		d.StreamListItem(ctx, row(item, meta))
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		return d.RowsRemaining(ctx) != 0, nil
+	})
+}
+
+// listPages calls fn for every item returned by a list endpoint, following the
+// next tokens from page to page, until fn returns false or an error, or the
+// context is done. The page size defaults to defaultPageSize.
+func listPages[T any](ctx context.Context, d *plugin.QueryData, name, resource string, input *api.QueryOptions, list listFunc[T], fn func(T) (bool, error)) error {
+	if input.PerPage == 0 {
+		input.PerPage = defaultPageSize
+	}
+
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		items, metadata, err := list(input)
+		if err != nil {
+			plugin.Logger(ctx).Error(name, "api_error", err)
+			return fmt.Errorf("failed to list %s: %w", resource, err)
+		}
+
+		for _, item := range items {
+			more, err := fn(item)
+			if err != nil {
+				return err
+			}
+			if !more {
+				return nil
+			}
+		}
+
+		// Stop on the last page, or if the endpoint returns the same token
+		// again rather than loop forever
+		if metadata == nil || metadata.NextToken == "" || metadata.NextToken == input.NextToken {
+			return nil
+		}
+		input.NextToken = metadata.NextToken
+	}
+}
+
+// pageSize returns the number of items to request per page of a list call.
+// The SQL LIMIT is only used when every qual of the query is an equality on
+// one of the pushedDown columns, i.e. is applied by Nomad, or on the
+// consistency column, which only sets the mode of the reads. Otherwise the SDK
+// filters the rows after they are streamed, so a page the size of the LIMIT
+// may hold fewer matching rows, and the default size saves on round trips.
+// The LIMIT of a table listed through a ParentHydrate counts its own rows
+// rather than the parents, so the parents are always read by the default size.
+func pageSize(d *plugin.QueryData, pushedDown ...string) int32 {
+	limit := d.QueryContext.Limit
+	if limit == nil || *limit <= 0 || *limit >= int64(defaultPageSize) {
+		return defaultPageSize
+	}
+	if d.Table.List != nil && d.Table.List.ParentHydrate != nil {
+		return defaultPageSize
+	}
+
+	for column, quals := range d.QueryContext.UnsafeQuals {
+		if column != "consistency" && !isPushedDown(column, pushedDown) {
+			return defaultPageSize
+		}
+		for _, qual := range quals.GetQuals() {
+			if qual.GetStringValue() != "=" {
+				return defaultPageSize
+			}
+			if _, ok := qual.GetValue().GetValue().(*proto.QualValue_ListValue); ok {
+				return defaultPageSize
+			}
+		}
+	}
+
+	return int32(*limit)
+}
+
+func isPushedDown(column string, pushedDown []string) bool {
+	for _, name := range pushedDown {
+		if name == column {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,228 @@
+package nomad
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+func TestPageSize(t *testing.T) {
+	for name, tc := range map[string]struct {
+		quals map[string]*proto.Quals
+		limit int64
+		want  string
+	}{
+		"no limit": {
+			want: "1000",
+		},
+		"limit": {
+			limit: 5,
+			want:  "5",
+		},
+		"limit above the default page size": {
+			limit: 5000,
+			want:  "1000",
+		},
+		"limit with pushed down quals": {
+			quals: equalsQuals(map[string]*proto.QualValue{"namespace": stringQual("apps"), "name": stringQual("web")}),
+			limit: 5,
+			want:  "5",
+		},
+		"limit with a qual filtered after streaming": {
+			quals: equalsQuals(map[string]*proto.QualValue{"status": stringQual("running")}),
+			limit: 5,
+			want:  "1000",
+		},
+		"limit with a key column qual not applied by Nomad": {
+			quals: equalsQuals(map[string]*proto.QualValue{"create_index": int64Qual(10)}),
+			limit: 5,
+			want:  "1000",
+		},
+		"limit with a pushed down column compared with another operator": {
+			quals: map[string]*proto.Quals{"name": {Quals: []*proto.Qual{newQual("name", "<>", stringQual("web"))}}},
+			limit: 5,
+			want:  "1000",
+		},
+		"limit with a list of values": {
+			quals: map[string]*proto.Quals{"name": {Quals: []*proto.Qual{newQual("name", "=", &proto.QualValue{Value: &proto.QualValue_ListValue{ListValue: &proto.QualValueList{
+				Values: []*proto.QualValue{stringQual("web"), stringQual("api")},
+			}}})}}},
+			limit: 5,
+			want:  "1000",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			f := newFakeNomad(t)
+			f.handle("/v1/jobs", []*api.JobListStub{})
+			server := newTestPluginServer(t, f, "")
+
+			testQuery{table: "nomad_job", columns: []string{"id"}, quals: tc.quals, limit: tc.limit}.mustExecute(t, server)
+
+			requests := f.requestsTo("/v1/jobs")
+			if len(requests) == 0 {
+				t.Fatal("expected jobs to be listed")
+			}
+			for _, request := range requests {
+				if perPage := request.URL.Query().Get("per_page"); perPage != tc.want {
+					t.Errorf("got per_page %s, want %s", perPage, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPageSizeKeepsPaging(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handlePages("/v1/nodes",
+		[]*api.NodeListStub{{ID: "node-1", Name: "client"}},
+		[]*api.NodeListStub{{ID: "node-2", Name: "client"}, {ID: "node-3", Name: "client"}},
+		[]*api.NodeListStub{{ID: "node-4", Name: "client"}},
+	)
+	server := newTestPluginServer(t, f, "")
+
+	// A page can hold fewer items than requested, so the next pages are read
+	// until the limit is reached
+	rows := testQuery{
+		table:   "nomad_node",
+		columns: []string{"id", "name"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"name": stringQual("client")}),
+		limit:   3,
+	}.mustExecute(t, server)
+	if len(rows) != 3 {
+		t.Errorf("got %d rows, want the limit", len(rows))
+	}
+
+	requests := f.requestsTo("/v1/nodes")
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want the pages up to the limit", len(requests))
+	}
+	for _, request := range requests {
+		if perPage := request.URL.Query().Get("per_page"); perPage != "3" {
+			t.Errorf("got per_page %s, want the limit", perPage)
+		}
+	}
+}
+
+func TestPageSizeParentHydrate(t *testing.T) {
+	for table, path := range map[string]string{
+		"nomad_acl_policy_rule":               "/v1/acl/policies",
+		"nomad_acl_auth_method_claim_mapping": "/v1/acl/auth-methods",
+	} {
+		t.Run(table, func(t *testing.T) {
+			f := newFakeNomad(t)
+			f.handle(path, []interface{}{})
+			server := newTestPluginServer(t, f, "")
+
+			// The LIMIT counts the rows of the child table, not the parents
+			testQuery{table: table, columns: []string{"title"}, limit: 1}.mustExecute(t, server)
+
+			requests := f.requestsTo(path)
+			if len(requests) == 0 {
+				t.Fatalf("expected %s to be listed", path)
+			}
+			if perPage := requests[0].URL.Query().Get("per_page"); perPage != "1000" {
+				t.Errorf("got per_page %s, want the default page size", perPage)
+			}
+		})
+	}
+}
+
+func TestListPages(t *testing.T) {
+	pages := map[string][]string{"": {"a", "b"}, "1": {"c"}, "2": {"d"}}
+	list := func(input *api.QueryOptions) ([]string, *api.QueryMeta, error) {
+		next := map[string]string{"": "1", "1": "2"}[input.NextToken]
+		return pages[input.NextToken], &api.QueryMeta{NextToken: next}, nil
+	}
+	d := &plugin.QueryData{}
+
+	tests := []struct {
+		name string
+		fn   func(string) (bool, error)
+		want []string
+		err  bool
+	}{
+		{
+			name: "all pages",
+			fn:   func(string) (bool, error) { return true, nil },
+			want: []string{"a", "b", "c", "d"},
+		},
+		{
+			name: "stops when fn returns false",
+			fn:   func(item string) (bool, error) { return item != "c", nil },
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "returns the error of fn",
+			fn:   func(item string) (bool, error) { return true, errors.New("failed") },
+			want: []string{"a"},
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			input := &api.QueryOptions{}
+			err := listPages(context.Background(), d, "test", "items", input, list, func(item string) (bool, error) {
+				got = append(got, item)
+				return test.fn(item)
+			})
+			if (err != nil) != test.err {
+				t.Fatalf("got error %v, want error %t", err, test.err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+			if input.PerPage != defaultPageSize {
+				t.Errorf("got page size %d, want the default", input.PerPage)
+			}
+		})
+	}
+}
+
+func TestListPagesStops(t *testing.T) {
+	d := &plugin.QueryData{}
+	calls := 0
+	// An endpoint returning the same token again must not be listed forever
+	list := func(*api.QueryOptions) ([]string, *api.QueryMeta, error) {
+		calls++
+		return []string{"a"}, &api.QueryMeta{NextToken: "1"}, nil
+	}
+	keep := func(string) (bool, error) { return true, nil }
+
+	if err := listPages(context.Background(), d, "test", "items", &api.QueryOptions{}, list, keep); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("got %d calls, want the same token to stop the listing", calls)
+	}
+
+	// A cancelled context stops before the next page
+	calls = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := listPages(ctx, d, "test", "items", &api.QueryOptions{}, list, keep); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 0 {
+		t.Errorf("got %d calls, want none once the context is done", calls)
+	}
+}
+
+func TestListPagesWrapsErrors(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handleError("/v1/nodes", http.StatusForbidden, "Permission denied")
+	server := newTestPluginServer(t, f, "")
+
+	_, err := testQuery{table: "nomad_node", columns: []string{"id"}}.execute(t, server)
+	if err == nil || !strings.Contains(err.Error(), "failed to list nodes") {
+		t.Errorf("got error %v, want it to name the listed resource", err)
+	}
+}
@@ -19,20 +19,40 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 		ConnectionConfigSchema: &plugin.ConnectionConfigSchema{
 			NewInstance: ConfigInstance,
 		},
+		SchemaMode: plugin.SchemaModeDynamic,
 		TableMap: map[string]*plugin.Table{
-			"nomad_acl_auth_method":  tableNomadACLAuthMethod(ctx),
-			"nomad_acl_binding_rule": tableNomadACLBindingRule(ctx),
-			"nomad_acl_policy":       tableNomadACLPolicy(ctx),
-			"nomad_acl_role":         tableNomadACLRole(ctx),
-			"nomad_acl_token":        tableNomadACLToken(ctx),
-			"nomad_agent_member":     tableNomadAgentMember(ctx),
-			"nomad_deployment":       tableNomadDeployment(ctx),
-			"nomad_job":              tableNomadJob(ctx),
-			"nomad_namespace":        tableNomadNamespace(ctx),
-			"nomad_node":             tableNomadNode(ctx),
-			"nomad_plugin":           tableNomadPlugin(ctx),
-			"nomad_volume":           tableNomadVolume(ctx),
+			"nomad_acl_auth_method":                tableNomadACLAuthMethod(ctx),
+			"nomad_acl_auth_method_claim_mapping":  tableNomadACLAuthMethodClaimMapping(ctx),
+			"nomad_acl_binding_rule":               tableNomadACLBindingRule(ctx),
+			"nomad_acl_binding_rule_evaluation":    tableNomadACLBindingRuleEvaluation(ctx),
+			"nomad_acl_policy":                     tableNomadACLPolicy(ctx),
+			"nomad_acl_policy_rule":                tableNomadACLPolicyRule(ctx),
+			"nomad_acl_role":                       tableNomadACLRole(ctx),
+			"nomad_acl_token":                      tableNomadACLToken(ctx),
+			"nomad_acl_token_effective_permission": tableNomadACLTokenEffectivePermission(ctx),
+			"nomad_acl_token_finding":              tableNomadACLTokenFinding(ctx),
+			"nomad_agent_member":                   tableNomadAgentMember(ctx),
+			"nomad_allocation_file":                tableNomadAllocationFile(ctx),
+			"nomad_allocation_log":                 tableNomadAllocationLog(ctx),
+			"nomad_allocation_task_event":          tableNomadAllocationTaskEvent(ctx),
+			"nomad_allocation_task_state":          tableNomadAllocationTaskState(ctx),
+			"nomad_deployment":                     tableNomadDeployment(ctx),
+			"nomad_event_stream":                   tableNomadEventStream(ctx),
+			"nomad_job":                            tableNomadJob(ctx),
+			"nomad_job_scale_status":               tableNomadJobScaleStatus(ctx),
+			"nomad_license":                        tableNomadLicense(ctx),
+			"nomad_namespace":                      tableNomadNamespace(ctx),
+			"nomad_node":                           tableNomadNode(ctx),
+			"nomad_node_device":                    tableNomadNodeDevice(ctx),
+			"nomad_node_event":                     tableNomadNodeEvent(ctx),
+			"nomad_plugin":                         tableNomadPlugin(ctx),
+			"nomad_quota_specification":            tableNomadQuotaSpecification(ctx),
+			"nomad_quota_usage":                    tableNomadQuotaUsage(ctx),
+			"nomad_scaling_policy":                 tableNomadScalingPolicy(ctx),
+			"nomad_sentinel_policy":                tableNomadSentinelPolicy(ctx),
+			"nomad_volume":                         tableNomadVolume(ctx),
 		},
 	}
+	p.TableMapFunc = tableMapWithValidation(p.TableMap)
 	return p
 }
@@ -0,0 +1,302 @@
+package nomad
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"text/tabwriter"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/iancoleman/strcase"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the column contract tests")
+
+// TestTableColumns compares the columns of every table with the golden files
+// in testdata/columns, so that renamed columns, changed types and transforms
+// resolving to different fields are caught. Run the tests with -update to
+// accept intended changes.
+func TestTableColumns(t *testing.T) {
+	p := Plugin(context.Background())
+
+	for name, table := range p.TableMap {
+		t.Run(name, func(t *testing.T) {
+			got := columnContract(p, table)
+			path := filepath.Join("testdata", "columns", name+".golden")
+
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("missing golden file for the %s table, run the tests with -update to create it: %v", name, err)
+			}
+			if got != string(want) {
+				t.Errorf("the columns of the %s table changed, run the tests with -update if this is intended\ngot:\n%s\nwant:\n%s", name, got, want)
+			}
+		})
+	}
+
+	// Golden files of removed tables must be removed too
+	files, err := filepath.Glob(filepath.Join("testdata", "columns", "*.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".golden")
+		if _, ok := p.TableMap[name]; !ok {
+			t.Errorf("golden file %s has no matching table", file)
+		}
+	}
+}
+
+// TestTableDocs checks that every table has a doc and every doc a table.
+func TestTableDocs(t *testing.T) {
+	p := Plugin(context.Background())
+	docs := filepath.Join("..", "docs", "tables")
+
+	for name := range p.TableMap {
+		if _, err := os.Stat(filepath.Join(docs, name+".md")); err != nil {
+			t.Errorf("missing doc for the %s table: %v", name, err)
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(docs, "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".md")
+		if _, ok := p.TableMap[name]; !ok {
+			t.Errorf("doc %s has no matching table", file)
+		}
+	}
+}
+
+// rowTypes maps the list, get and column hydrate functions of the tables to
+// the types of the items they return.
+var rowTypes = map[string]reflect.Type{
+	"nomad.listACLAuthMethodClaimMappings":   reflect.TypeOf(aclAuthMethodClaimMappingInfo{}),
+	"nomad.listACLAuthMethods":               reflect.TypeOf(aclAuthMethodListRow{}),
+	"nomad.getACLAuthMethod":                 reflect.TypeOf(aclAuthMethodRow{}),
+	"nomad.getACLAuthMethodConfig":           reflect.TypeOf(&api.ACLAuthMethodConfig{}),
+	"nomad.listACLBindingRuleEvaluations":    reflect.TypeOf(aclBindingRuleEvaluationInfo{}),
+	"nomad.listACLBindingRules":              reflect.TypeOf(aclBindingRuleListRow{}),
+	"nomad.getACLBindingRule":                reflect.TypeOf(aclBindingRuleRow{}),
+	"nomad.listACLPolicies":                  reflect.TypeOf(aclPolicyListRow{}),
+	"nomad.getACLPolicy":                     reflect.TypeOf(aclPolicyRow{}),
+	"nomad.listACLPolicyRules":               reflect.TypeOf(aclPolicyRuleInfo{}),
+	"nomad.listACLRoles":                     reflect.TypeOf(aclRoleListRow{}),
+	"nomad.getACLRole":                       reflect.TypeOf(aclRoleRow{}),
+	"nomad.listACLTokenEffectivePermissions": reflect.TypeOf(aclTokenEffectivePermissionInfo{}),
+	"nomad.listACLTokenFindings":             reflect.TypeOf(aclTokenFindingInfo{}),
+	"nomad.listACLTokens":                    reflect.TypeOf(aclTokenListRow{}),
+	"nomad.getACLToken":                      reflect.TypeOf(aclTokenRow{}),
+	"nomad.getACLTokenSecretID":              reflect.TypeOf(""),
+	"nomad.listAgentMembers":                 reflect.TypeOf(&api.AgentMember{}),
+	"nomad.listAllocationFiles":              reflect.TypeOf(allocationFileInfo{}),
+	"nomad.getAllocationFileContent":         reflect.TypeOf(allocationFileInfo{}),
+	"nomad.listAllocationLogs":               reflect.TypeOf(allocationLogLine{}),
+	"nomad.listAllocationTaskEvents":         reflect.TypeOf(allocationTaskEventInfo{}),
+	"nomad.listAllocationTaskStates":         reflect.TypeOf(allocationTaskStateInfo{}),
+	"nomad.listDeployments":                  reflect.TypeOf(deploymentRow{}),
+	"nomad.getDeployment":                    reflect.TypeOf(deploymentRow{}),
+	"nomad.listEventStream":                  reflect.TypeOf(eventStreamEvent{}),
+	"nomad.listJobScaleStatuses":             reflect.TypeOf(jobScaleStatusInfo{}),
+	"nomad.listJobs":                         reflect.TypeOf(jobListRow{}),
+	"nomad.getJob":                           reflect.TypeOf(jobRow{}),
+	"nomad.getJobConsulToken":                reflect.TypeOf(""),
+	"nomad.getJobVaultToken":                 reflect.TypeOf(""),
+	"nomad.listLicense":                      reflect.TypeOf(licenseInfo{}),
+	"nomad.listNamespaces":                   reflect.TypeOf(namespaceRow{}),
+	"nomad.getNamespace":                     reflect.TypeOf(namespaceRow{}),
+	"nomad.listNodeDevices":                  reflect.TypeOf(nodeDeviceInfo{}),
+	"nomad.listNodeEvents":                   reflect.TypeOf(nodeEventInfo{}),
+	"nomad.listNodes":                        reflect.TypeOf(nodeListRow{}),
+	"nomad.getNode":                          reflect.TypeOf(nodeRow{}),
+	"nomad.listPlugins":                      reflect.TypeOf(csiPluginListRow{}),
+	"nomad.getPlugin":                        reflect.TypeOf(csiPluginRow{}),
+	"nomad.listQuotaSpecifications":          reflect.TypeOf(quotaSpecificationLimitInfo{}),
+	"nomad.listQuotaUsages":                  reflect.TypeOf(quotaUsageInfo{}),
+	"nomad.getQueryMeta":                     reflect.TypeOf(queryMetaInfo{}),
+	"nomad.listScalingPolicies":              reflect.TypeOf(scalingPolicyListRow{}),
+	"nomad.getScalingPolicy":                 reflect.TypeOf(scalingPolicyRow{}),
+	"nomad.listSentinelPolicies":             reflect.TypeOf(sentinelPolicyListRow{}),
+	"nomad.getSentinelPolicy":                reflect.TypeOf(sentinelPolicyRow{}),
+	"nomad.listVolumes":                      reflect.TypeOf(csiVolumeListRow{}),
+	"nomad.getVolume":                        reflect.TypeOf(csiVolumeRow{}),
+	"nomad.getVolumeSecrets":                 reflect.TypeOf(map[string]string{}),
+}
+
+// TestTableFields checks that every field path read by the column transforms
+// exists on the items the column is populated from: the items of its hydrate
+// function, or else the items of both the list and the get functions of the
+// table. A missing field is not an error in the SDK, the column is just null.
+func TestTableFields(t *testing.T) {
+	p := Plugin(context.Background())
+
+	for name, table := range p.TableMap {
+		t.Run(name, func(t *testing.T) {
+			for _, column := range table.Columns {
+				hydrates := []string{}
+				if column.Hydrate != nil {
+					hydrates = append(hydrates, funcName(column.Hydrate))
+				} else {
+					hydrates = append(hydrates, funcName(table.List.Hydrate))
+					if table.Get != nil {
+						hydrates = append(hydrates, funcName(table.Get.Hydrate))
+					}
+				}
+
+				transforms := column.Transform
+				if transforms == nil {
+					transforms = p.DefaultTransform
+				}
+
+				for _, hydrate := range hydrates {
+					rowType, ok := rowTypes[hydrate]
+					if !ok {
+						t.Errorf("no row type registered for %s, add it to rowTypes", hydrate)
+						continue
+					}
+					for _, call := range transforms.Transforms {
+						for _, path := range fieldPaths(column.Name, call) {
+							if !hasFieldPath(rowType, path) {
+								t.Errorf("the %s column reads the %s field, which %s does not have", column.Name, path, rowType)
+							}
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+// columnContract renders the name, type, hydrate and transforms of each column
+// of the table, one column per line.
+func columnContract(p *plugin.Plugin, table *plugin.Table) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COLUMN\tTYPE\tHYDRATE\tTRANSFORM")
+
+	for _, column := range table.Columns {
+		hydrate := "-"
+		if column.Hydrate != nil {
+			hydrate = funcName(column.Hydrate)
+		}
+		transforms := column.Transform
+		if transforms == nil {
+			transforms = p.DefaultTransform
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", column.Name, column.Type, hydrate, transformContract(column.Name, transforms))
+	}
+
+	w.Flush()
+	return b.String()
+}
+
+// transformContract renders a transform chain. The field read by the camel
+// case and Go transforms is resolved from the column name, so that a change in
+// the name conversion shows up as a different field.
+func transformContract(columnName string, transforms *transform.ColumnTransforms) string {
+	var calls []string
+	for _, call := range transforms.Transforms {
+		name := funcName(call.Transform)
+		param := call.Param
+		switch name {
+		case "transform.FieldValueCamelCase", "transform.FieldValueGo":
+			param = fieldPaths(columnName, call)[0]
+		}
+
+		switch param := param.(type) {
+		case nil:
+			calls = append(calls, name)
+		case string:
+			calls = append(calls, fmt.Sprintf("%s(%q)", name, param))
+		case []string:
+			fields := make([]string, len(param))
+			for i, field := range param {
+				fields[i] = fmt.Sprintf("%q", field)
+			}
+			calls = append(calls, fmt.Sprintf("%s(%s)", name, strings.Join(fields, ", ")))
+		default:
+			calls = append(calls, fmt.Sprintf("%s(%v)", name, param))
+		}
+	}
+	return strings.Join(calls, " | ")
+}
+
+// funcName returns the package qualified name of a function.
+func funcName(fn interface{}) string {
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// fieldPaths returns the field paths read from the hydrate item by a
+// transform, resolving the paths of the camel case and Go transforms from the
+// column name as the SDK does.
+func fieldPaths(columnName string, call *transform.TransformCall) []string {
+	switch funcName(call.Transform) {
+	case "transform.FieldValue":
+		switch param := call.Param.(type) {
+		case string:
+			return []string{param}
+		case []string:
+			return param
+		}
+	case "transform.FieldValueCamelCase":
+		return []string{strcase.ToCamel(columnName)}
+	case "transform.FieldValueGo":
+		return []string{helpers.LintName(strcase.ToCamel(columnName))}
+	}
+	return nil
+}
+
+// hasFieldPath reports whether the dot separated field path can be read from
+// a value of type t. As in the SDK, map keys are not known in advance and
+// fields are only promoted from embedded structs, not embedded pointers.
+func hasFieldPath(t reflect.Type, path string) bool {
+	for _, name := range strings.Split(path, ".") {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			field, ok := t.FieldByName(name)
+			if !ok || !field.IsExported() {
+				return false
+			}
+			embedded := t
+			for _, i := range field.Index[:len(field.Index)-1] {
+				embedded = embedded.Field(i).Type
+				if embedded.Kind() == reflect.Ptr {
+					return false
+				}
+			}
+			t = field.Type
+		case reflect.Map:
+			t = t.Elem()
+		case reflect.Interface:
+			return true
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+package nomad
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+// redactedSecretPrefix prefixes the fingerprint returned in place of a secret
+const redactedSecretPrefix = "redacted:hmac-sha256:"
+
+// redactionKey keys the fingerprints of the redacted secrets. It is generated
+// when the plugin starts and never leaves it, so that a fingerprint cannot be
+// matched against the hashes of guessed secrets.
+var redactionKey = newRedactionKey()
+
+func newRedactionKey() []byte {
+	key := make([]byte, 32)
+	// Read never returns an error, and crashes the program if the system
+	// random number generator fails
+	rand.Read(key)
+	return key
+}
+
+// shouldRevealSecrets returns true if the connection is configured to return
+// sensitive values in clear text.
+func shouldRevealSecrets(d *plugin.QueryData) bool {
+	nomadConfig := GetConfig(d.Connection)
+	return nomadConfig.RevealSecrets != nil && *nomadConfig.RevealSecrets
+}
+
+// redactSecret returns a fingerprint of the secret unless the connection is
+// configured to reveal secrets. The fingerprint can be compared across the rows
+// and queries of a Steampipe session without exposing the secret itself.
+func redactSecret(d *plugin.QueryData, secret string) string {
+	if secret == "" || shouldRevealSecrets(d) {
+		return secret
+	}
+	mac := hmac.New(sha256.New, redactionKey)
+	mac.Write([]byte(secret))
+	return redactedSecretPrefix + hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// redactSecretMap redacts every value of the map, keeping its keys.
+func redactSecretMap(d *plugin.QueryData, secrets map[string]string) map[string]string {
+	if secrets == nil || shouldRevealSecrets(d) {
+		return secrets
+	}
+	redacted := make(map[string]string, len(secrets))
+	for key, secret := range secrets {
+		redacted[key] = redactSecret(d, secret)
+	}
+	return redacted
+}
+
+//// HYDRATE FUNCTIONS
+
+// The hydrate functions below depend on the hydrate function fetching the
+// resource and redact its sensitive fields.
+
+func getACLAuthMethodConfig(_ context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	authMethod, ok := h.HydrateResults["getACLAuthMethod"].(aclAuthMethodRow)
+	if !ok || authMethod.Config == nil {
+		return nil, nil
+	}
+
+	config := *authMethod.Config
+	config.OIDCClientSecret = redactSecret(d, config.OIDCClientSecret)
+	return &config, nil
+}
+
+func getACLTokenSecretID(_ context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	token, ok := h.HydrateResults["getACLToken"].(aclTokenRow)
+	if !ok {
+		return nil, nil
+	}
+	return redactSecret(d, token.SecretID), nil
+}
+
+func getJobConsulToken(_ context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	job, ok := h.HydrateResults["getJob"].(jobRow)
+	if !ok || job.ConsulToken == nil {
+		return nil, nil
+	}
+	return redactSecret(d, *job.ConsulToken), nil
+}
+
+func getJobVaultToken(_ context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	job, ok := h.HydrateResults["getJob"].(jobRow)
+	if !ok || job.VaultToken == nil {
+		return nil, nil
+	}
+	return redactSecret(d, *job.VaultToken), nil
+}
+
+func getVolumeSecrets(_ context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	volume, ok := h.HydrateResults["getVolume"].(csiVolumeRow)
+	if !ok {
+		return nil, nil
+	}
+	return redactSecretMap(d, volume.Secrets), nil
+}
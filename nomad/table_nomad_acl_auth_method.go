@@ -9,18 +9,37 @@ import (
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
 )
 
+type aclAuthMethodListRow struct {
+	api.ACLAuthMethodListStub
+	queryMetaRow
+}
+
+type aclAuthMethodRow struct {
+	api.ACLAuthMethod
+	queryMetaRow
+}
+
 func tableNomadACLAuthMethod(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
 		Name:        "nomad_acl_auth_method",
 		Description: "Retrieve information about your ACL auth methods.",
 		List: &plugin.ListConfig{
 			Hydrate: listACLAuthMethods,
+			KeyColumns: []*plugin.KeyColumn{
+				consistencyKeyColumn(),
+			},
 		},
 		Get: &plugin.GetConfig{
-			KeyColumns: plugin.SingleColumn("name"),
+			KeyColumns: append(plugin.SingleColumn("name"), consistencyKeyColumn()),
 			Hydrate:    getACLAuthMethod,
 		},
-		Columns: []*plugin.Column{
+		HydrateConfig: []plugin.HydrateConfig{
+			{
+				Func:    getACLAuthMethodConfig,
+				Depends: []plugin.HydrateFunc{getACLAuthMethod},
+			},
+		},
+		Columns: queryMetaColumns([]*plugin.Column{
 			{
 				Name:        "name",
 				Type:        proto.ColumnType_STRING,
@@ -76,8 +95,128 @@ func tableNomadACLAuthMethod(ctx context.Context) *plugin.Table {
 			{
 				Name:        "config",
 				Type:        proto.ColumnType_JSON,
-				Description: "Config contains the detailed configuration which is specific to the auth method.",
-				Hydrate:     getACLAuthMethod,
+				Description: "Config contains the detailed configuration which is specific to the auth method. The OIDC client secret is redacted to a fingerprint unless reveal_secrets is set in the connection config.",
+				Hydrate:     getACLAuthMethodConfig,
+				Transform:   transform.FromValue(),
+			},
+			{
+				Name:        "jwt_validation_pub_keys",
+				Type:        proto.ColumnType_JSON,
+				Description: "A list of PEM-encoded public keys used to authenticate signatures locally.",
+				Transform:   transform.FromField("JWTValidationPubKeys"),
+				Hydrate:     getACLAuthMethodConfig,
+			},
+			{
+				Name:        "jwks_url",
+				Type:        proto.ColumnType_STRING,
+				Description: "The JSON Web Key Sets URL used to authenticate signatures.",
+				Transform:   transform.FromField("JWKSURL"),
+				Hydrate:     getACLAuthMethodConfig,
+			},
+			{
+				Name:        "jwks_ca_cert",
+				Type:        proto.ColumnType_STRING,
+				Description: "The PEM-encoded CA certificate used by the TLS client talking to the JWKS URL.",
+				Transform:   transform.FromField("JWKSCACert"),
+				Hydrate:     getACLAuthMethodConfig,
+			},
+			{
+				Name:        "oidc_discovery_url",
+				Type:        proto.ColumnType_STRING,
+				Description: "The OIDC discovery URL, without any .well-known component.",
+				Transform:   transform.FromField("OIDCDiscoveryURL"),
+				Hydrate:     getACLAuthMethodConfig,
+			},
+			{
+				Name:        "oidc_client_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The OAuth client ID configured with the OIDC provider.",
+				Transform:   transform.FromField("OIDCClientID"),
+				Hydrate:     getACLAuthMethodConfig,
+			},
+			{
+				Name:        "oidc_client_secret",
+				Type:        proto.ColumnType_STRING,
+				Description: "The OAuth client secret configured with the OIDC provider. Redacted to a fingerprint unless reveal_secrets is set in the connection config.",
+				Transform:   transform.FromField("OIDCClientSecret"),
+				Hydrate:     getACLAuthMethodConfig,
+			},
+			{
+				Name:        "oidc_scopes",
+				Type:        proto.ColumnType_JSON,
+				Description: "The list of OIDC scopes requested on login.",
+				Transform:   transform.FromField("OIDCScopes"),
+				Hydrate:     getACLAuthMethodConfig,
+			},
+			{
+				Name:        "bound_audiences",
+				Type:        proto.ColumnType_JSON,
+				Description: "The list of audiences allowed in the aud claim of a token.",
+				Transform:   transform.FromField("BoundAudiences"),
+				Hydrate:     getACLAuthMethodConfig,
+			},
+			{
+				Name:        "bound_issuer",
+				Type:        proto.ColumnType_JSON,
+				Description: "The list of values allowed in the iss claim of a token.",
+				Transform:   transform.FromField("BoundIssuer"),
+				Hydrate:     getACLAuthMethodConfig,
+			},
+			{
+				Name:        "allowed_redirect_uris",
+				Type:        proto.ColumnType_JSON,
+				Description: "The list of allowed values for the redirect URI.",
+				Transform:   transform.FromField("AllowedRedirectURIs"),
+				Hydrate:     getACLAuthMethodConfig,
+			},
+			{
+				Name:        "discovery_ca_pem",
+				Type:        proto.ColumnType_JSON,
+				Description: "The list of PEM-encoded CA certificates used by the TLS client talking to the OIDC discovery URL.",
+				Transform:   transform.FromField("DiscoveryCaPem"),
+				Hydrate:     getACLAuthMethodConfig,
+			},
+			{
+				Name:        "signing_algs",
+				Type:        proto.ColumnType_JSON,
+				Description: "The list of supported signing algorithms.",
+				Transform:   transform.FromField("SigningAlgs"),
+				Hydrate:     getACLAuthMethodConfig,
+			},
+			{
+				Name:        "expiration_leeway",
+				Type:        proto.ColumnType_STRING,
+				Description: "The leeway applied when validating the expiration of a token to account for clock skew.",
+				Transform:   transform.FromField("ExpirationLeeway").NullIfZero(),
+				Hydrate:     getACLAuthMethodConfig,
+			},
+			{
+				Name:        "not_before_leeway",
+				Type:        proto.ColumnType_STRING,
+				Description: "The leeway applied when validating the not before value of a token to account for clock skew.",
+				Transform:   transform.FromField("NotBeforeLeeway").NullIfZero(),
+				Hydrate:     getACLAuthMethodConfig,
+			},
+			{
+				Name:        "clock_skew_leeway",
+				Type:        proto.ColumnType_STRING,
+				Description: "The leeway applied when validating all claims to account for clock skew.",
+				Transform:   transform.FromField("ClockSkewLeeway").NullIfZero(),
+				Hydrate:     getACLAuthMethodConfig,
+			},
+			{
+				Name:        "claim_mappings",
+				Type:        proto.ColumnType_JSON,
+				Description: "The mappings of claims to the metadata names available to binding rules under value.",
+				Transform:   transform.FromField("ClaimMappings"),
+				Hydrate:     getACLAuthMethodConfig,
+			},
+			{
+				Name:        "list_claim_mappings",
+				Type:        proto.ColumnType_JSON,
+				Description: "The mappings of list claims to the metadata names available to binding rules under list.",
+				Transform:   transform.FromField("ListClaimMappings"),
+				Hydrate:     getACLAuthMethodConfig,
 			},
 
 			/// Steampipe standard columns
@@ -87,7 +226,7 @@ func tableNomadACLAuthMethod(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromField("Name"),
 			},
-		},
+		}),
 	}
 }
 
@@ -98,36 +237,13 @@ func listACLAuthMethods(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydr
 		return nil, err
 	}
 
-	maxLimit := int64(1000)
-	if d.QueryContext.Limit != nil {
-		if *d.QueryContext.Limit < maxLimit {
-			maxLimit = *d.QueryContext.Limit
-		}
-	}
-
-	input := &api.QueryOptions{
-		PerPage: int32(maxLimit),
-	}
-
-	for {
-		authMethods, metadata, err := client.ACLAuthMethods().List(input)
-		if err != nil {
-			plugin.Logger(ctx).Error("nomad_acl_auth_method.listACLAuthMethods", "api_error", err)
-			return nil, err
-		}
+	input := queryOptions(d)
+	input.PerPage = pageSize(d)
 
-		for _, authMethod := range authMethods {
-			d.StreamListItem(ctx, authMethod)
-
-			// Context can be cancelled due to manual cancellation or the limit has been hit
-			if d.RowsRemaining(ctx) == 0 {
-				return nil, nil
-			}
-		}
-		input.NextToken = metadata.NextToken
-		if input.NextToken == "" {
-			break
-		}
+	if err := streamPages(ctx, d, "nomad_acl_auth_method.listACLAuthMethods", "ACL auth methods", input, client.ACLAuthMethods().List, func(item *api.ACLAuthMethodListStub, meta *api.QueryMeta) interface{} {
+		return aclAuthMethodListRow{ACLAuthMethodListStub: *item, queryMetaRow: queryMetaRow{meta: meta}}
+	}); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
@@ -137,7 +253,7 @@ func getACLAuthMethod(ctx context.Context, d *plugin.QueryData, h *plugin.Hydrat
 	logger := plugin.Logger(ctx)
 	var name string
 	if h.Item != nil {
-		name = h.Item.(*api.ACLAuthMethodListStub).Name
+		name = h.Item.(aclAuthMethodListRow).Name
 	} else {
 		name = d.EqualsQualString("name")
 	}
@@ -154,11 +270,11 @@ func getACLAuthMethod(ctx context.Context, d *plugin.QueryData, h *plugin.Hydrat
 		return nil, err
 	}
 
-	authMethod, _, err := client.ACLAuthMethods().Get(name, &api.QueryOptions{})
+	authMethod, meta, err := client.ACLAuthMethods().Get(name, queryOptions(d))
 	if err != nil {
 		logger.Error("nomad_acl_auth_method.getACLAuthMethod", "api_error", err)
 		return nil, err
 	}
 
-	return authMethod, nil
+	return aclAuthMethodRow{ACLAuthMethod: *authMethod, queryMetaRow: queryMetaRow{meta: meta}}, nil
 }
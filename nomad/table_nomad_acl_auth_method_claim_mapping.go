@@ -0,0 +1,127 @@
+package nomad
+
+import (
+	"context"
+	"sort"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+type aclAuthMethodClaimMappingInfo struct {
+	AuthMethod     string
+	AuthMethodType string
+	Claim          string
+	MetadataName   string
+	IsList         bool
+}
+
+func tableNomadACLAuthMethodClaimMapping(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_acl_auth_method_claim_mapping",
+		Description: "Retrieve the claim mappings of your ACL auth methods.",
+		List: &plugin.ListConfig{
+			ParentHydrate: listACLAuthMethods,
+			Hydrate:       listACLAuthMethodClaimMappings,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "auth_method",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "auth_method",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the acl auth method.",
+			},
+			{
+				Name:        "auth_method_type",
+				Type:        proto.ColumnType_STRING,
+				Description: "The type of the acl auth method, either OIDC or JWT.",
+			},
+			{
+				Name:        "claim",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the claim, or a JSON pointer to it if it starts with a slash.",
+			},
+			{
+				Name:        "metadata_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name the claim is mapped to, referenced by binding rule selectors as value.<name> or list.<name>.",
+			},
+			{
+				Name:        "is_list",
+				Type:        proto.ColumnType_BOOL,
+				Description: "True if the claim is mapped as a list claim.",
+			},
+
+			/// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "The title of the acl auth method claim mapping.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Claim"),
+			},
+		},
+	}
+}
+
+func listACLAuthMethodClaimMappings(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	name := h.Item.(aclAuthMethodListRow).Name
+
+	// Skip the auth methods not matching the requested name
+	if d.EqualsQualString("auth_method") != "" && d.EqualsQualString("auth_method") != name {
+		return nil, nil
+	}
+
+	// Create client
+	client, err := getClient(ctx, d)
+	if err != nil {
+		logger.Error("nomad_acl_auth_method_claim_mapping.listACLAuthMethodClaimMappings", "connection_error", err)
+		return nil, err
+	}
+
+	authMethod, _, err := client.ACLAuthMethods().Get(name, queryOptions(d))
+	if err != nil {
+		logger.Error("nomad_acl_auth_method_claim_mapping.listACLAuthMethodClaimMappings", "api_error", err)
+		return nil, err
+	}
+	if authMethod == nil || authMethod.Config == nil {
+		return nil, nil
+	}
+
+	for _, mappings := range []struct {
+		claims map[string]string
+		isList bool
+	}{
+		{authMethod.Config.ClaimMappings, false},
+		{authMethod.Config.ListClaimMappings, true},
+	} {
+		claims := make([]string, 0, len(mappings.claims))
+		for claim := range mappings.claims {
+			claims = append(claims, claim)
+		}
+		sort.Strings(claims)
+
+		for _, claim := range claims {
+			d.StreamListItem(ctx, aclAuthMethodClaimMappingInfo{
+				AuthMethod:     authMethod.Name,
+				AuthMethodType: authMethod.Type,
+				Claim:          claim,
+				MetadataName:   mappings.claims[claim],
+				IsList:         mappings.isList,
+			})
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
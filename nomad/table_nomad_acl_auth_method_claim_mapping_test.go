@@ -0,0 +1,30 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+func TestListACLAuthMethodClaimMappings(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/auth-methods", []*api.ACLAuthMethodListStub{{Name: "oidc", Type: "OIDC"}})
+	f.handle("/v1/acl/auth-method/oidc", testACLAuthMethod())
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_acl_auth_method_claim_mapping",
+		columns: []string{"auth_method", "auth_method_type", "claim", "metadata_name", "is_list"},
+	}.mustExecute(t, server)
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3", len(rows))
+	}
+	for _, row := range rows {
+		if row.string("auth_method") != "oidc" || row.string("auth_method_type") != "OIDC" {
+			t.Errorf("unexpected row: %v", row)
+		}
+		if isList := row.string("claim") == "groups"; row.bool("is_list") != isList {
+			t.Errorf("got is_list %v for the %s claim", row.bool("is_list"), row.string("claim"))
+		}
+	}
+}
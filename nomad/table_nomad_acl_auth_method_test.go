@@ -0,0 +1,71 @@
+package nomad
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func testACLAuthMethod() *api.ACLAuthMethod {
+	return &api.ACLAuthMethod{
+		Name:          "oidc",
+		Type:          "OIDC",
+		TokenLocality: "local",
+		Config: &api.ACLAuthMethodConfig{
+			OIDCDiscoveryURL:  "https://idp.example.com",
+			OIDCClientID:      "nomad",
+			OIDCClientSecret:  "client-secret",
+			BoundAudiences:    []string{"nomad"},
+			ClaimMappings:     map[string]string{"email": "email", "team": "team"},
+			ListClaimMappings: map[string]string{"groups": "groups"},
+			ClockSkewLeeway:   30 * time.Second,
+		},
+	}
+}
+
+func TestListACLAuthMethods(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handlePages("/v1/acl/auth-methods",
+		[]*api.ACLAuthMethodListStub{{Name: "oidc", Type: "OIDC", Default: true}},
+		[]*api.ACLAuthMethodListStub{{Name: "jwt", Type: "JWT"}},
+	)
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{table: "nomad_acl_auth_method", columns: []string{"name", "type", "default_auth_method"}}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 rows across both pages", len(rows))
+	}
+	for _, row := range rows {
+		if row.string("name") == "oidc" && (row.string("type") != "OIDC" || !row.bool("default_auth_method")) {
+			t.Errorf("unexpected oidc row: %v", row)
+		}
+	}
+}
+
+func TestGetACLAuthMethod(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/auth-method/oidc", testACLAuthMethod())
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_acl_auth_method",
+		columns: []string{"name", "token_locality", "oidc_discovery_url", "oidc_client_secret", "bound_audiences", "config", "clock_skew_leeway", "expiration_leeway", "not_before_leeway"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"name": stringQual("oidc")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(rows))
+	}
+	row := rows[0]
+	if row.string("token_locality") != "local" || row.string("oidc_discovery_url") != "https://idp.example.com" || row.json("bound_audiences") != `["nomad"]` {
+		t.Errorf("unexpected row: %v", row)
+	}
+	if !strings.HasPrefix(row.string("oidc_client_secret"), redactedSecretPrefix) || strings.Contains(row.json("config"), "client-secret") {
+		t.Errorf("expected the OIDC client secret to be redacted, got %q and %s", row.string("oidc_client_secret"), row.json("config"))
+	}
+	if row.string("clock_skew_leeway") != "30s" || !row.isNull("expiration_leeway") || !row.isNull("not_before_leeway") {
+		t.Errorf("expected only the set leeway to be returned, got %v", row)
+	}
+}
@@ -9,18 +9,31 @@ import (
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
 )
 
+type aclBindingRuleListRow struct {
+	api.ACLBindingRuleListStub
+	queryMetaRow
+}
+
+type aclBindingRuleRow struct {
+	api.ACLBindingRule
+	queryMetaRow
+}
+
 func tableNomadACLBindingRule(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
 		Name:        "nomad_acl_binding_rule",
 		Description: "Retrieve information about your ACL binding rules.",
 		List: &plugin.ListConfig{
 			Hydrate: listACLBindingRules,
+			KeyColumns: []*plugin.KeyColumn{
+				consistencyKeyColumn(),
+			},
 		},
 		Get: &plugin.GetConfig{
-			KeyColumns: plugin.SingleColumn("id"),
+			KeyColumns: append(plugin.SingleColumn("id"), consistencyKeyColumn()),
 			Hydrate:    getACLBindingRule,
 		},
-		Columns: []*plugin.Column{
+		Columns: queryMetaColumns([]*plugin.Column{
 			{
 				Name:        "id",
 				Type:        proto.ColumnType_STRING,
@@ -85,7 +98,7 @@ func tableNomadACLBindingRule(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromField("ID"),
 			},
-		},
+		}),
 	}
 }
 
@@ -96,36 +109,13 @@ func listACLBindingRules(ctx context.Context, d *plugin.QueryData, _ *plugin.Hyd
 		return nil, err
 	}
 
-	maxLimit := int64(1000)
-	if d.QueryContext.Limit != nil {
-		if *d.QueryContext.Limit < maxLimit {
-			maxLimit = *d.QueryContext.Limit
-		}
-	}
-
-	input := &api.QueryOptions{
-		PerPage: int32(maxLimit),
-	}
+	input := queryOptions(d)
+	input.PerPage = pageSize(d)
 
-	for {
-		bindingRules, metadata, err := client.ACLBindingRules().List(input)
-		if err != nil {
-			plugin.Logger(ctx).Error("nomad_acl_binding_rule.listACLBindingRules", "api_error", err)
-			return nil, err
-		}
-
-		for _, bindingRule := range bindingRules {
-			d.StreamListItem(ctx, bindingRule)
-
-			// Context can be cancelled due to manual cancellation or the limit has been hit
-			if d.RowsRemaining(ctx) == 0 {
-				return nil, nil
-			}
-		}
-		input.NextToken = metadata.NextToken
-		if input.NextToken == "" {
-			break
-		}
+	if err := streamPages(ctx, d, "nomad_acl_binding_rule.listACLBindingRules", "ACL binding rules", input, client.ACLBindingRules().List, func(item *api.ACLBindingRuleListStub, meta *api.QueryMeta) interface{} {
+		return aclBindingRuleListRow{ACLBindingRuleListStub: *item, queryMetaRow: queryMetaRow{meta: meta}}
+	}); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
@@ -135,7 +125,7 @@ func getACLBindingRule(ctx context.Context, d *plugin.QueryData, h *plugin.Hydra
 	logger := plugin.Logger(ctx)
 	var id string
 	if h.Item != nil {
-		id = h.Item.(*api.ACLBindingRuleListStub).ID
+		id = h.Item.(aclBindingRuleListRow).ID
 	} else {
 		id = d.EqualsQualString("id")
 	}
@@ -152,11 +142,11 @@ func getACLBindingRule(ctx context.Context, d *plugin.QueryData, h *plugin.Hydra
 		return nil, err
 	}
 
-	bindingRule, _, err := client.ACLBindingRules().Get(id, &api.QueryOptions{})
+	bindingRule, meta, err := client.ACLBindingRules().Get(id, queryOptions(d))
 	if err != nil {
 		logger.Error("nomad_acl_binding_rule.getACLBindingRule", "api_error", err)
 		return nil, err
 	}
 
-	return bindingRule, nil
+	return aclBindingRuleRow{ACLBindingRule: *bindingRule, queryMetaRow: queryMetaRow{meta: meta}}, nil
 }
@@ -0,0 +1,222 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+type aclBindingRuleEvaluationInfo struct {
+	AuthMethod       string
+	Claims           map[string]interface{}
+	MappedClaims     *aclAuthClaims
+	BindingRuleID    string
+	Description      string
+	Selector         string
+	BindType         string
+	BindName         string
+	Matched          bool
+	ResolvedBindName string
+	Valid            bool
+	Error            string
+}
+
+func tableNomadACLBindingRuleEvaluation(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_acl_binding_rule_evaluation",
+		Description: "Evaluate the ACL binding rules of an auth method against a sample set of identity claims.",
+		List: &plugin.ListConfig{
+			Hydrate: listACLBindingRuleEvaluations,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "auth_method",
+					Require: plugin.Required,
+				},
+				{
+					Name:    "claims",
+					Require: plugin.Required,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "auth_method",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the auth method the binding rules are evaluated for.",
+			},
+			{
+				Name:        "claims",
+				Type:        proto.ColumnType_JSON,
+				Description: "The sample identity claims, as returned by the identity provider, the binding rules are evaluated against.",
+			},
+			{
+				Name:        "mapped_claims",
+				Type:        proto.ColumnType_JSON,
+				Description: "The claims once mapped through the claim mappings of the auth method, as available to selectors under value and list.",
+			},
+			{
+				Name:        "binding_rule_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the acl binding rule.",
+				Transform:   transform.FromField("BindingRuleID"),
+			},
+			{
+				Name:        "description",
+				Type:        proto.ColumnType_STRING,
+				Description: "The description of the acl binding rule.",
+			},
+			{
+				Name:        "selector",
+				Type:        proto.ColumnType_STRING,
+				Description: "The selector expression of the acl binding rule.",
+			},
+			{
+				Name:        "bind_type",
+				Type:        proto.ColumnType_STRING,
+				Description: "The binding type of the acl binding rule, one of role, policy or management.",
+			},
+			{
+				Name:        "bind_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The binding name of the acl binding rule, before interpolation.",
+			},
+			{
+				Name:        "matched",
+				Type:        proto.ColumnType_BOOL,
+				Description: "True if the selector of the binding rule matches the claims.",
+			},
+			{
+				Name:        "resolved_bind_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The binding name interpolated with the mapped claims. Only set if the binding rule matched.",
+				Transform:   transform.FromField("ResolvedBindName").NullIfZero(),
+			},
+			{
+				Name:        "valid",
+				Type:        proto.ColumnType_BOOL,
+				Description: "True if the binding rule matched and its resolved binding name is valid for the binding type.",
+			},
+			{
+				Name:        "error",
+				Type:        proto.ColumnType_STRING,
+				Description: "The error raised evaluating the selector or interpolating the binding name, if any.",
+				Transform:   transform.FromField("Error").NullIfZero(),
+			},
+
+			/// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "The title of the acl binding rule evaluation.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("BindingRuleID"),
+			},
+		},
+	}
+}
+
+func listACLBindingRuleEvaluations(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	name := d.EqualsQualString("auth_method")
+	if name == "" || d.EqualsQuals["claims"] == nil {
+		return nil, nil
+	}
+
+	claims, err := parseACLAuthClaims(d.EqualsQuals["claims"].GetJsonbValue())
+	if err != nil {
+		logger.Error("nomad_acl_binding_rule_evaluation.listACLBindingRuleEvaluations", "parse_error", err)
+		return nil, err
+	}
+
+	// Create client
+	client, err := getClient(ctx, d)
+	if err != nil {
+		logger.Error("nomad_acl_binding_rule_evaluation.listACLBindingRuleEvaluations", "connection_error", err)
+		return nil, err
+	}
+
+	authMethod, _, err := client.ACLAuthMethods().Get(name, queryOptions(d))
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil, nil
+		}
+		logger.Error("nomad_acl_binding_rule_evaluation.listACLBindingRuleEvaluations", "api_error", err)
+		return nil, err
+	}
+	if authMethod == nil {
+		return nil, nil
+	}
+
+	mapped, err := mapACLAuthClaims(authMethod.Config, claims)
+	if err != nil {
+		logger.Error("nomad_acl_binding_rule_evaluation.listACLBindingRuleEvaluations", "claim_mapping_error", err)
+		return nil, err
+	}
+
+	evaluate := func(stub *api.ACLBindingRuleListStub) (bool, error) {
+		if stub.AuthMethod != authMethod.Name {
+			return true, nil
+		}
+		bindingRule, _, err := client.ACLBindingRules().Get(stub.ID, queryOptions(d))
+		if err != nil {
+			if isNotFoundError(err) {
+				return true, nil
+			}
+			logger.Error("nomad_acl_binding_rule_evaluation.listACLBindingRuleEvaluations", "api_error", err)
+			return false, err
+		}
+
+		d.StreamListItem(ctx, evaluateACLBindingRule(authMethod, bindingRule, claims, mapped))
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		return d.RowsRemaining(ctx) != 0, nil
+	}
+
+	if err := listPages(ctx, d, "nomad_acl_binding_rule_evaluation.listACLBindingRuleEvaluations", "ACL binding rules", queryOptions(d), client.ACLBindingRules().List, evaluate); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// evaluateACLBindingRule applies a binding rule to the mapped claims the same
+// way Nomad does when an identity logs in through the auth method.
+func evaluateACLBindingRule(authMethod *api.ACLAuthMethod, bindingRule *api.ACLBindingRule, claims map[string]interface{}, mapped *aclAuthClaims) aclBindingRuleEvaluationInfo {
+	evaluation := aclBindingRuleEvaluationInfo{
+		AuthMethod:    authMethod.Name,
+		Claims:        claims,
+		MappedClaims:  mapped,
+		BindingRuleID: bindingRule.ID,
+		Description:   bindingRule.Description,
+		Selector:      bindingRule.Selector,
+		BindType:      bindingRule.BindType,
+		BindName:      bindingRule.BindName,
+	}
+
+	matched, err := matchACLBindingRuleSelector(bindingRule.Selector, mapped)
+	if err != nil {
+		evaluation.Error = err.Error()
+		return evaluation
+	}
+	if !matched {
+		return evaluation
+	}
+	evaluation.Matched = true
+
+	name, valid, err := computeACLBindName(authMethod.Config, bindingRule.BindType, bindingRule.BindName, mapped)
+	if err != nil {
+		evaluation.Error = err.Error()
+		return evaluation
+	}
+	evaluation.ResolvedBindName = name
+	evaluation.Valid = valid
+	if !valid {
+		evaluation.Error = fmt.Sprintf("computed %q bind name is invalid: %q", bindingRule.BindType, name)
+	}
+
+	return evaluation
+}
@@ -0,0 +1,67 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func TestListACLBindingRuleEvaluations(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/auth-method/oidc", testACLAuthMethod())
+	f.handle("/v1/acl/binding-rules", []*api.ACLBindingRuleListStub{
+		{ID: "rule-1", AuthMethod: "oidc"},
+		{ID: "rule-2", AuthMethod: "oidc"},
+		{ID: "rule-3", AuthMethod: "jwt"},
+	})
+	f.handle("/v1/acl/binding-rule/rule-1", &api.ACLBindingRule{ID: "rule-1", AuthMethod: "oidc", Selector: `"admins" in list.groups`, BindType: "role", BindName: "${value.team}-admin"})
+	f.handle("/v1/acl/binding-rule/rule-2", &api.ACLBindingRule{ID: "rule-2", AuthMethod: "oidc", Selector: `value.team == "dev"`, BindType: "policy", BindName: "dev"})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_acl_binding_rule_evaluation",
+		columns: []string{"auth_method", "binding_rule_id", "matched", "resolved_bind_name", "valid", "error"},
+		quals: equalsQuals(map[string]*proto.QualValue{
+			"auth_method": stringQual("oidc"),
+			"claims":      jsonQual(`{"email": "jane@example.com", "team": "ops", "groups": ["admins"]}`),
+		}),
+	}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want one per binding rule of the auth method", len(rows))
+	}
+	for _, row := range rows {
+		switch row.string("binding_rule_id") {
+		case "rule-1":
+			if !row.bool("matched") || !row.bool("valid") || row.string("resolved_bind_name") != "ops-admin" {
+				t.Errorf("unexpected rule-1 evaluation: %v", row)
+			}
+		case "rule-2":
+			if row.bool("matched") || !row.isNull("resolved_bind_name") {
+				t.Errorf("unexpected rule-2 evaluation: %v", row)
+			}
+		default:
+			t.Errorf("unexpected evaluation of %s", row.string("binding_rule_id"))
+		}
+	}
+	if len(f.requestsTo("/v1/acl/binding-rule/rule-3")) != 0 {
+		t.Errorf("expected the binding rules of other auth methods not to be fetched")
+	}
+}
+
+func TestListACLBindingRuleEvaluationsInvalidClaims(t *testing.T) {
+	f := newFakeNomad(t)
+	server := newTestPluginServer(t, f, "")
+
+	_, err := testQuery{
+		table:   "nomad_acl_binding_rule_evaluation",
+		columns: []string{"binding_rule_id"},
+		quals: equalsQuals(map[string]*proto.QualValue{
+			"auth_method": stringQual("oidc"),
+			"claims":      jsonQual(`["not", "an", "object"]`),
+		}),
+	}.execute(t, server)
+	if err == nil {
+		t.Errorf("expected an error for claims that are not a JSON object")
+	}
+}
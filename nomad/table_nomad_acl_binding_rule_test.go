@@ -0,0 +1,44 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func TestListACLBindingRules(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handlePages("/v1/acl/binding-rules",
+		[]*api.ACLBindingRuleListStub{{ID: "rule-1", AuthMethod: "oidc", Description: "Admins"}},
+		[]*api.ACLBindingRuleListStub{{ID: "rule-2", AuthMethod: "jwt"}},
+	)
+	f.handle("/v1/acl/binding-rule/rule-1", &api.ACLBindingRule{ID: "rule-1", AuthMethod: "oidc", Selector: `"admins" in list.groups`, BindType: "management"})
+	f.handle("/v1/acl/binding-rule/rule-2", &api.ACLBindingRule{ID: "rule-2", AuthMethod: "jwt", BindType: "role", BindName: "ci"})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{table: "nomad_acl_binding_rule", columns: []string{"id", "auth_method", "selector", "bind_type"}}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 rows across both pages", len(rows))
+	}
+	for _, row := range rows {
+		if row.string("id") == "rule-1" && (row.string("selector") != `"admins" in list.groups` || row.string("bind_type") != "management") {
+			t.Errorf("unexpected rule-1 row: %v", row)
+		}
+	}
+}
+
+func TestGetACLBindingRule(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/binding-rule/rule-2", &api.ACLBindingRule{ID: "rule-2", AuthMethod: "jwt", BindType: "role", BindName: "ci"})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_acl_binding_rule",
+		columns: []string{"id", "bind_type", "bind_name"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"id": stringQual("rule-2")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].string("bind_type") != "role" || rows[0].string("bind_name") != "ci" {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+}
@@ -9,18 +9,31 @@ import (
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
 )
 
+type aclPolicyListRow struct {
+	api.ACLPolicyListStub
+	queryMetaRow
+}
+
+type aclPolicyRow struct {
+	api.ACLPolicy
+	queryMetaRow
+}
+
 func tableNomadACLPolicy(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
 		Name:        "nomad_acl_policy",
 		Description: "Retrieve information about your ACL policies.",
 		List: &plugin.ListConfig{
 			Hydrate: listACLPolicies,
+			KeyColumns: []*plugin.KeyColumn{
+				consistencyKeyColumn(),
+			},
 		},
 		Get: &plugin.GetConfig{
-			KeyColumns: plugin.SingleColumn("name"),
+			KeyColumns: append(plugin.SingleColumn("name"), consistencyKeyColumn()),
 			Hydrate:    getACLPolicy,
 		},
-		Columns: []*plugin.Column{
+		Columns: queryMetaColumns([]*plugin.Column{
 			{
 				Name:        "name",
 				Type:        proto.ColumnType_STRING,
@@ -37,6 +50,13 @@ func tableNomadACLPolicy(ctx context.Context) *plugin.Table {
 				Description: "The set of rules of the acl policy.",
 				Hydrate:     getACLPolicy,
 			},
+			{
+				Name:        "rules_parsed",
+				Type:        proto.ColumnType_JSON,
+				Description: "The rules of the acl policy parsed into a structured form, with the policy shorthands expanded into their capabilities.",
+				Hydrate:     getACLPolicy,
+				Transform:   transform.From(parseACLPolicyRulesTransform),
+			},
 			{
 				Name:        "create_index",
 				Type:        proto.ColumnType_INT,
@@ -62,7 +82,7 @@ func tableNomadACLPolicy(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromField("Name"),
 			},
-		},
+		}),
 	}
 }
 
@@ -73,36 +93,13 @@ func listACLPolicies(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrate
 		return nil, err
 	}
 
-	maxLimit := int64(1000)
-	if d.QueryContext.Limit != nil {
-		if *d.QueryContext.Limit < maxLimit {
-			maxLimit = *d.QueryContext.Limit
-		}
-	}
-
-	input := &api.QueryOptions{
-		PerPage: int32(maxLimit),
-	}
-
-	for {
-		policies, metadata, err := client.ACLPolicies().List(input)
-		if err != nil {
-			plugin.Logger(ctx).Error("nomad_acl_policy.listACLPolicies", "api_error", err)
-			return nil, err
-		}
+	input := queryOptions(d)
+	input.PerPage = pageSize(d)
 
-		for _, policy := range policies {
-			d.StreamListItem(ctx, policy)
-
-			// Context can be cancelled due to manual cancellation or the limit has been hit
-			if d.RowsRemaining(ctx) == 0 {
-				return nil, nil
-			}
-		}
-		input.NextToken = metadata.NextToken
-		if input.NextToken == "" {
-			break
-		}
+	if err := streamPages(ctx, d, "nomad_acl_policy.listACLPolicies", "ACL policies", input, client.ACLPolicies().List, func(item *api.ACLPolicyListStub, meta *api.QueryMeta) interface{} {
+		return aclPolicyListRow{ACLPolicyListStub: *item, queryMetaRow: queryMetaRow{meta: meta}}
+	}); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
@@ -112,7 +109,7 @@ func getACLPolicy(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDat
 	logger := plugin.Logger(ctx)
 	var name string
 	if h.Item != nil {
-		name = h.Item.(*api.ACLPolicyListStub).Name
+		name = h.Item.(aclPolicyListRow).Name
 	} else {
 		name = d.EqualsQualString("name")
 	}
@@ -129,11 +126,11 @@ func getACLPolicy(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDat
 		return nil, err
 	}
 
-	policy, _, err := client.ACLPolicies().Info(name, &api.QueryOptions{})
+	policy, meta, err := client.ACLPolicies().Info(name, queryOptions(d))
 	if err != nil {
 		logger.Error("nomad_acl_policy.getACLPolicy", "api_error", err)
 		return nil, err
 	}
 
-	return policy, nil
+	return aclPolicyRow{ACLPolicy: *policy, queryMetaRow: queryMetaRow{meta: meta}}, nil
 }
@@ -0,0 +1,127 @@
+package nomad
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+type aclPolicyRuleInfo struct {
+	PolicyName   string
+	Scope        string
+	Target       string
+	VariablePath string
+	Policy       string
+	Capability   string
+}
+
+func tableNomadACLPolicyRule(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_acl_policy_rule",
+		Description: "Retrieve the capabilities granted by each rule of your ACL policies.",
+		List: &plugin.ListConfig{
+			ParentHydrate: listACLPolicies,
+			Hydrate:       listACLPolicyRules,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "policy_name",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "policy_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the acl policy.",
+			},
+			{
+				Name:        "scope",
+				Type:        proto.ColumnType_STRING,
+				Description: "The type of the rule block, one of namespace, variables, host_volume, agent, node, operator, quota or plugin.",
+			},
+			{
+				Name:        "target",
+				Type:        proto.ColumnType_STRING,
+				Description: "The namespace or host volume the rule applies to, which may contain a glob. Empty for the agent, node, operator, quota and plugin scopes.",
+				Transform:   transform.FromField("Target").NullIfZero(),
+			},
+			{
+				Name:        "variable_path",
+				Type:        proto.ColumnType_STRING,
+				Description: "The variable path the rule applies to, which may contain a glob. Only set for the variables scope.",
+				Transform:   transform.FromField("VariablePath").NullIfZero(),
+			},
+			{
+				Name:        "policy",
+				Type:        proto.ColumnType_STRING,
+				Description: "The policy shorthand of the rule block the capability was expanded from, e.g. read or write.",
+				Transform:   transform.FromField("Policy").NullIfZero(),
+			},
+			{
+				Name:        "capability",
+				Type:        proto.ColumnType_STRING,
+				Description: "The capability granted by the rule, e.g. submit-job or read-logs.",
+			},
+
+			/// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "The title of the acl policy rule.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Capability"),
+			},
+		},
+	}
+}
+
+func listACLPolicyRules(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	name := h.Item.(aclPolicyListRow).Name
+
+	// Skip the policies not matching the requested name
+	if d.EqualsQualString("policy_name") != "" && d.EqualsQualString("policy_name") != name {
+		return nil, nil
+	}
+
+	// Create client
+	client, err := getClient(ctx, d)
+	if err != nil {
+		logger.Error("nomad_acl_policy_rule.listACLPolicyRules", "connection_error", err)
+		return nil, err
+	}
+
+	policy, _, err := client.ACLPolicies().Info(name, queryOptions(d))
+	if err != nil {
+		logger.Error("nomad_acl_policy_rule.listACLPolicyRules", "api_error", err)
+		return nil, err
+	}
+
+	// A policy whose rules cannot be parsed is skipped rather than failing
+	// the rules of every other policy
+	rules, err := parseACLPolicyRules(policy.Rules)
+	if err != nil {
+		logger.Warn("nomad_acl_policy_rule.listACLPolicyRules", "policy_name", name, "parse_error", err)
+		return nil, nil
+	}
+
+	for _, capability := range rules.Capabilities() {
+		d.StreamListItem(ctx, aclPolicyRuleInfo{
+			PolicyName:   policy.Name,
+			Scope:        capability.Scope,
+			Target:       capability.Target,
+			VariablePath: capability.VariablePath,
+			Policy:       capability.Policy,
+			Capability:   capability.Capability,
+		})
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,61 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func TestListACLPolicyRules(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/policies", []*api.ACLPolicyListStub{{Name: "readonly"}, {Name: "ops"}})
+	f.handle("/v1/acl/policy/readonly", &api.ACLPolicy{Name: "readonly", Rules: testACLPolicyRules})
+	f.handle("/v1/acl/policy/ops", &api.ACLPolicy{Name: "ops", Rules: `operator { policy = "write" }`})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_acl_policy_rule",
+		columns: []string{"policy_name", "scope", "target", "policy", "capability"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"policy_name": stringQual("readonly")}),
+	}.mustExecute(t, server)
+
+	found := map[string]bool{}
+	for _, row := range rows {
+		if row.string("policy_name") != "readonly" {
+			t.Errorf("got a rule of the %q policy, want only the readonly policy", row.string("policy_name"))
+		}
+		found[row.string("scope")+"/"+row.string("target")+"/"+row.string("capability")] = true
+	}
+	for _, want := range []string{"namespace/default/read-job", "node//read"} {
+		if !found[want] {
+			t.Errorf("missing the %s capability in %v", want, found)
+		}
+	}
+	if len(f.requestsTo("/v1/acl/policy/ops")) != 0 {
+		t.Errorf("expected the ops policy not to be fetched")
+	}
+}
+
+func TestListACLPolicyRulesInvalidRules(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/policies", []*api.ACLPolicyListStub{{Name: "broken"}, {Name: "ops"}})
+	f.handle("/v1/acl/policy/broken", &api.ACLPolicy{Name: "broken", Rules: `namespace "default" {`})
+	f.handle("/v1/acl/policy/ops", &api.ACLPolicy{Name: "ops", Rules: `operator { policy = "write" }`})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_acl_policy_rule",
+		columns: []string{"policy_name", "scope", "capability"},
+	}.mustExecute(t, server)
+
+	// The broken policy is skipped and the rules of the other policy returned
+	if len(rows) == 0 {
+		t.Fatalf("got no rows, want the rules of the ops policy")
+	}
+	for _, row := range rows {
+		if row.string("policy_name") != "ops" || row.string("scope") != "operator" {
+			t.Errorf("unexpected row: %v", row)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+const testACLPolicyRules = `namespace "default" {
+  policy = "read"
+}
+
+node {
+  policy = "read"
+}
+`
+
+func TestListACLPolicies(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handlePages("/v1/acl/policies",
+		[]*api.ACLPolicyListStub{{Name: "readonly", Description: "Read only access"}},
+		[]*api.ACLPolicyListStub{{Name: "ops", Description: "Operators"}},
+	)
+	f.handle("/v1/acl/policy/readonly", &api.ACLPolicy{Name: "readonly", Description: "Read only access", Rules: testACLPolicyRules})
+	f.handle("/v1/acl/policy/ops", &api.ACLPolicy{Name: "ops", Description: "Operators", Rules: `operator { policy = "write" }`})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{table: "nomad_acl_policy", columns: []string{"name", "description", "rules"}}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 rows across both pages", len(rows))
+	}
+	for _, row := range rows {
+		if row.string("name") == "readonly" && row.string("rules") != testACLPolicyRules {
+			t.Errorf("got rules %q for the readonly policy, want them from the policy", row.string("rules"))
+		}
+	}
+}
+
+func TestGetACLPolicy(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/policy/readonly", &api.ACLPolicy{Name: "readonly", Rules: testACLPolicyRules})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_acl_policy",
+		columns: []string{"name", "rules", "rules_parsed"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"name": stringQual("readonly")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].isNull("rules_parsed") {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+	if len(f.requestsTo("/v1/acl/policies")) != 0 {
+		t.Errorf("expected the policy to be fetched without listing policies")
+	}
+}
+
+func TestGetACLPolicyNotFound(t *testing.T) {
+	f := newFakeNomad(t)
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_acl_policy",
+		columns: []string{"name"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"name": stringQual("missing")}),
+	}.mustExecute(t, server)
+	if len(rows) != 0 {
+		t.Errorf("got %d rows, want none for a missing policy", len(rows))
+	}
+}
@@ -9,18 +9,31 @@ import (
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
 )
 
+type aclRoleListRow struct {
+	api.ACLRoleListStub
+	queryMetaRow
+}
+
+type aclRoleRow struct {
+	api.ACLRole
+	queryMetaRow
+}
+
 func tableNomadACLRole(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
 		Name:        "nomad_acl_role",
 		Description: "Retrieve information about your ACL roles.",
 		List: &plugin.ListConfig{
 			Hydrate: listACLRoles,
+			KeyColumns: []*plugin.KeyColumn{
+				consistencyKeyColumn(),
+			},
 		},
 		Get: &plugin.GetConfig{
-			KeyColumns: plugin.SingleColumn("id"),
+			KeyColumns: append(plugin.SingleColumn("id"), consistencyKeyColumn()),
 			Hydrate:    getACLRole,
 		},
-		Columns: []*plugin.Column{
+		Columns: queryMetaColumns([]*plugin.Column{
 			{
 				Name:        "id",
 				Type:        proto.ColumnType_STRING,
@@ -60,7 +73,7 @@ func tableNomadACLRole(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromField("Name"),
 			},
-		},
+		}),
 	}
 }
 
@@ -71,36 +84,13 @@ func listACLRoles(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDat
 		return nil, err
 	}
 
-	maxLimit := int64(1000)
-	if d.QueryContext.Limit != nil {
-		if *d.QueryContext.Limit < maxLimit {
-			maxLimit = *d.QueryContext.Limit
-		}
-	}
-
-	input := &api.QueryOptions{
-		PerPage: int32(maxLimit),
-	}
+	input := queryOptions(d)
+	input.PerPage = pageSize(d)
 
-	for {
-		roles, metadata, err := client.ACLRoles().List(input)
-		if err != nil {
-			plugin.Logger(ctx).Error("nomad_acl_role.listACLRoles", "api_error", err)
-			return nil, err
-		}
-
-		for _, role := range roles {
-			d.StreamListItem(ctx, role)
-
-			// Context can be cancelled due to manual cancellation or the limit has been hit
-			if d.RowsRemaining(ctx) == 0 {
-				return nil, nil
-			}
-		}
-		input.NextToken = metadata.NextToken
-		if input.NextToken == "" {
-			break
-		}
+	if err := streamPages(ctx, d, "nomad_acl_role.listACLRoles", "ACL roles", input, client.ACLRoles().List, func(item *api.ACLRoleListStub, meta *api.QueryMeta) interface{} {
+		return aclRoleListRow{ACLRoleListStub: *item, queryMetaRow: queryMetaRow{meta: meta}}
+	}); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
@@ -110,7 +100,7 @@ func getACLRole(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData)
 	logger := plugin.Logger(ctx)
 	var id string
 	if h.Item != nil {
-		id = h.Item.(*api.ACLRoleListStub).ID
+		id = h.Item.(aclRoleListRow).ID
 	} else {
 		id = d.EqualsQualString("id")
 	}
@@ -127,11 +117,11 @@ func getACLRole(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData)
 		return nil, err
 	}
 
-	role, _, err := client.ACLRoles().Get(id, &api.QueryOptions{})
+	role, meta, err := client.ACLRoles().Get(id, queryOptions(d))
 	if err != nil {
 		logger.Error("nomad_acl_role.getACLRole", "api_error", err)
 		return nil, err
 	}
 
-	return role, nil
+	return aclRoleRow{ACLRole: *role, queryMetaRow: queryMetaRow{meta: meta}}, nil
 }
@@ -0,0 +1,42 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func TestListACLRoles(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handlePages("/v1/acl/roles",
+		[]*api.ACLRoleListStub{{ID: "role-1", Name: "developers", Policies: []*api.ACLRolePolicyLink{{Name: "readonly"}}}},
+		[]*api.ACLRoleListStub{{ID: "role-2", Name: "operators"}},
+	)
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{table: "nomad_acl_role", columns: []string{"id", "name", "policies"}}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 rows across both pages", len(rows))
+	}
+	for _, row := range rows {
+		if row.string("id") == "role-1" && row.json("policies") != `[{"Name":"readonly"}]` {
+			t.Errorf("got policies %s for role-1", row.json("policies"))
+		}
+	}
+}
+
+func TestGetACLRole(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/role/role-1", &api.ACLRole{ID: "role-1", Name: "developers", Description: "Developers"})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_acl_role",
+		columns: []string{"id", "name", "description"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"id": stringQual("role-1")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].string("name") != "developers" || rows[0].string("description") != "Developers" {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+}
@@ -10,6 +10,16 @@ import (
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
 )
 
+type aclTokenListRow struct {
+	api.ACLTokenListStub
+	queryMetaRow
+}
+
+type aclTokenRow struct {
+	api.ACLToken
+	queryMetaRow
+}
+
 func tableNomadACLToken(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
 		Name:        "nomad_acl_token",
@@ -21,13 +31,20 @@ func tableNomadACLToken(ctx context.Context) *plugin.Table {
 					Name:    "name",
 					Require: plugin.Optional,
 				},
+				consistencyKeyColumn(),
 			},
 		},
 		Get: &plugin.GetConfig{
-			KeyColumns: plugin.SingleColumn("accessor_id"),
+			KeyColumns: append(plugin.SingleColumn("accessor_id"), consistencyKeyColumn()),
 			Hydrate:    getACLToken,
 		},
-		Columns: []*plugin.Column{
+		HydrateConfig: []plugin.HydrateConfig{
+			{
+				Func:    getACLTokenSecretID,
+				Depends: []plugin.HydrateFunc{getACLToken},
+			},
+		},
+		Columns: queryMetaColumns([]*plugin.Column{
 			{
 				Name:        "accessor_id",
 				Type:        proto.ColumnType_STRING,
@@ -37,9 +54,9 @@ func tableNomadACLToken(ctx context.Context) *plugin.Table {
 			{
 				Name:        "secret_id",
 				Type:        proto.ColumnType_STRING,
-				Description: "The secret ID of the acl token.",
-				Transform:   transform.FromField("SecretID"),
-				Hydrate:     getACLToken,
+				Description: "The secret ID of the acl token. Redacted to a fingerprint unless reveal_secrets is set in the connection config.",
+				Transform:   transform.FromValue(),
+				Hydrate:     getACLTokenSecretID,
 			},
 			{
 				Name:        "name",
@@ -101,7 +118,7 @@ func tableNomadACLToken(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromField("Name"),
 			},
-		},
+		}),
 	}
 }
 
@@ -112,40 +129,17 @@ func listACLTokens(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDa
 		return nil, err
 	}
 
-	maxLimit := int64(1000)
-	if d.QueryContext.Limit != nil {
-		if *d.QueryContext.Limit < maxLimit {
-			maxLimit = *d.QueryContext.Limit
-		}
-	}
-
-	input := &api.QueryOptions{
-		PerPage: int32(maxLimit),
-	}
+	input := queryOptions(d)
+	input.PerPage = pageSize(d, "name")
 	if d.EqualsQualString("name") != "" {
 		filter := fmt.Sprintf("Name== %q\n", d.EqualsQualString("name"))
 		input.Filter = filter
 	}
 
-	for {
-		tokens, metadata, err := client.ACLTokens().List(input)
-		if err != nil {
-			plugin.Logger(ctx).Error("nomad_acl_token.listACLTokens", "api_error", err)
-			return nil, err
-		}
-
-		for _, token := range tokens {
-			d.StreamListItem(ctx, token)
-
-			// Context can be cancelled due to manual cancellation or the limit has been hit
-			if d.RowsRemaining(ctx) == 0 {
-				return nil, nil
-			}
-		}
-		input.NextToken = metadata.NextToken
-		if input.NextToken == "" {
-			break
-		}
+	if err := streamPages(ctx, d, "nomad_acl_token.listACLTokens", "ACL tokens", input, client.ACLTokens().List, func(item *api.ACLTokenListStub, meta *api.QueryMeta) interface{} {
+		return aclTokenListRow{ACLTokenListStub: *item, queryMetaRow: queryMetaRow{meta: meta}}
+	}); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
@@ -155,7 +149,7 @@ func getACLToken(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData
 	logger := plugin.Logger(ctx)
 	var accessorID string
 	if h.Item != nil {
-		accessorID = h.Item.(*api.ACLTokenListStub).AccessorID
+		accessorID = h.Item.(aclTokenListRow).AccessorID
 	} else {
 		accessorID = d.EqualsQualString("accessor_id")
 	}
@@ -172,11 +166,11 @@ func getACLToken(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData
 		return nil, err
 	}
 
-	token, _, err := client.ACLTokens().Info(accessorID, &api.QueryOptions{})
+	token, meta, err := client.ACLTokens().Info(accessorID, queryOptions(d))
 	if err != nil {
 		logger.Error("nomad_acl_token.getACLToken", "api_error", err)
 		return nil, err
 	}
 
-	return token, nil
+	return aclTokenRow{ACLToken: *token, queryMetaRow: queryMetaRow{meta: meta}}, nil
 }
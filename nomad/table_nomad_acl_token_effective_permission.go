@@ -0,0 +1,319 @@
+package nomad
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+// aclTokenTypeManagement is the type of tokens granted every permission
+const aclTokenTypeManagement = "management"
+
+type aclTokenEffectivePermissionInfo struct {
+	AccessorID   string
+	TokenName    string
+	TokenType    string
+	Management   bool
+	ResourceType string
+	Target       string
+	VariablePath string
+	Capability   string
+	Sources      []string
+}
+
+func tableNomadACLTokenEffectivePermission(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_acl_token_effective_permission",
+		Description: "Retrieve the effective permissions of your ACL tokens, resolved from their policies and roles.",
+		List: &plugin.ListConfig{
+			Hydrate: listACLTokenEffectivePermissions,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "accessor_id",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "accessor_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The accessor ID of the acl token.",
+				Transform:   transform.FromField("AccessorID"),
+			},
+			{
+				Name:        "token_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the acl token.",
+			},
+			{
+				Name:        "token_type",
+				Type:        proto.ColumnType_STRING,
+				Description: "The type of the acl token, either client or management.",
+			},
+			{
+				Name:        "management",
+				Type:        proto.ColumnType_BOOL,
+				Description: "True if the token is a management token, which is granted every permission.",
+			},
+			{
+				Name:        "resource_type",
+				Type:        proto.ColumnType_STRING,
+				Description: "The type of resource the capability applies to, one of namespace, variables, host_volume, agent, node, operator, quota or plugin. Set to * for management tokens.",
+			},
+			{
+				Name:        "target",
+				Type:        proto.ColumnType_STRING,
+				Description: "The namespace or host volume the capability applies to, which may contain a glob. Set to * for management tokens.",
+				Transform:   transform.FromField("Target").NullIfZero(),
+			},
+			{
+				Name:        "variable_path",
+				Type:        proto.ColumnType_STRING,
+				Description: "The variable path the capability applies to, which may contain a glob. Only set for the variables resource type.",
+				Transform:   transform.FromField("VariablePath").NullIfZero(),
+			},
+			{
+				Name:        "capability",
+				Type:        proto.ColumnType_STRING,
+				Description: "The capability granted to the token. A deny capability overrides every other capability for the same target. Set to * for management tokens.",
+			},
+			{
+				Name:        "sources",
+				Type:        proto.ColumnType_JSON,
+				Description: "The policies granting the capability, prefixed with the role name when inherited from a role.",
+			},
+
+			/// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "The title of the acl token effective permission.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Capability"),
+			},
+		},
+	}
+}
+
+func listACLTokenEffectivePermissions(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	// Create client
+	client, err := getClient(ctx, d)
+	if err != nil {
+		logger.Error("nomad_acl_token_effective_permission.listACLTokenEffectivePermissions", "connection_error", err)
+		return nil, err
+	}
+
+	// The roles and policies are shared by many tokens, so each is fetched
+	// once per query
+	resolver := newACLTokenPermissionResolver(ctx, d, client)
+
+	// Restrict to a single token if the accessor ID has been provided
+	if d.EqualsQualString("accessor_id") != "" {
+		token, _, err := client.ACLTokens().Info(d.EqualsQualString("accessor_id"), queryOptions(d))
+		if err != nil {
+			logger.Error("nomad_acl_token_effective_permission.listACLTokenEffectivePermissions", "api_error", err)
+			return nil, err
+		}
+		if _, err := streamACLTokenPermissions(ctx, d, resolver, token.AccessorID, token.Name, token.Type, token.Policies, token.Roles); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	input := queryOptions(d)
+	err = listPages(ctx, d, "nomad_acl_token_effective_permission.listACLTokenEffectivePermissions", "ACL tokens", input, client.ACLTokens().List, func(token *api.ACLTokenListStub) (bool, error) {
+		return streamACLTokenPermissions(ctx, d, resolver, token.AccessorID, token.Name, token.Type, token.Policies, token.Roles)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// streamACLTokenPermissions streams one row per effective permission of the
+// token and returns false once no further rows are required.
+func streamACLTokenPermissions(ctx context.Context, d *plugin.QueryData, resolver *aclTokenPermissionResolver, accessorID, name, tokenType string, policies []string, roles []*api.ACLTokenRoleLink) (bool, error) {
+	if tokenType == aclTokenTypeManagement {
+		d.StreamListItem(ctx, aclTokenEffectivePermissionInfo{
+			AccessorID:   accessorID,
+			TokenName:    name,
+			TokenType:    tokenType,
+			Management:   true,
+			ResourceType: "*",
+			Target:       "*",
+			Capability:   "*",
+		})
+		return d.RowsRemaining(ctx) != 0, nil
+	}
+
+	permissions, err := resolver.resolve(policies, roles)
+	if err != nil {
+		plugin.Logger(ctx).Error("nomad_acl_token_effective_permission.streamACLTokenPermissions", "api_error", err)
+		return false, err
+	}
+
+	for _, permission := range permissions {
+		permission.AccessorID = accessorID
+		permission.TokenName = name
+		permission.TokenType = tokenType
+		d.StreamListItem(ctx, permission)
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// aclTokenPermissionResolver resolves the permissions of tokens, memoizing the
+// roles and policies it fetches. A nil entry records a role or policy that no
+// longer exists, or a policy whose rules cannot be parsed.
+type aclTokenPermissionResolver struct {
+	ctx      context.Context
+	d        *plugin.QueryData
+	client   *api.Client
+	roles    map[string]*api.ACLRole
+	policies map[string]*aclPolicyRules
+}
+
+func newACLTokenPermissionResolver(ctx context.Context, d *plugin.QueryData, client *api.Client) *aclTokenPermissionResolver {
+	return &aclTokenPermissionResolver{
+		ctx:      ctx,
+		d:        d,
+		client:   client,
+		roles:    map[string]*api.ACLRole{},
+		policies: map[string]*aclPolicyRules{},
+	}
+}
+
+func (r *aclTokenPermissionResolver) role(id string) (*api.ACLRole, error) {
+	if role, ok := r.roles[id]; ok {
+		return role, nil
+	}
+	role, _, err := r.client.ACLRoles().Get(id, queryOptions(r.d))
+	if err != nil && !isNotFoundError(err) {
+		return nil, err
+	}
+	r.roles[id] = role
+	return role, nil
+}
+
+func (r *aclTokenPermissionResolver) policy(name string) (*aclPolicyRules, error) {
+	if rules, ok := r.policies[name]; ok {
+		return rules, nil
+	}
+	policy, _, err := r.client.ACLPolicies().Info(name, queryOptions(r.d))
+	if err != nil {
+		if !isNotFoundError(err) {
+			return nil, err
+		}
+		r.policies[name] = nil
+		return nil, nil
+	}
+	// As in the nomad_acl_policy_rule table, a policy whose rules cannot be
+	// parsed is skipped rather than failing the permissions of every token
+	rules, err := parseACLPolicyRules(policy.Rules)
+	if err != nil {
+		plugin.Logger(r.ctx).Warn("nomad_acl_token_effective_permission.policy", "policy_name", name, "parse_error", err)
+	}
+	r.policies[name] = rules
+	return rules, nil
+}
+
+// resolve merges the capabilities of the policies linked to a token, directly
+// or through its roles. Policies and roles that no longer exist grant nothing
+// and are skipped.
+func (r *aclTokenPermissionResolver) resolve(policies []string, roles []*api.ACLTokenRoleLink) ([]aclTokenEffectivePermissionInfo, error) {
+	// sources maps each policy name to the ways it is linked to the token
+	sources := map[string][]string{}
+	for _, name := range policies {
+		sources[name] = append(sources[name], name)
+	}
+	for _, link := range roles {
+		if link == nil {
+			continue
+		}
+		role, err := r.role(link.ID)
+		if err != nil {
+			return nil, err
+		}
+		if role == nil {
+			continue
+		}
+		for _, policy := range role.Policies {
+			if policy != nil {
+				sources[policy.Name] = append(sources[policy.Name], role.Name+"/"+policy.Name)
+			}
+		}
+	}
+
+	type permissionKey struct {
+		resourceType string
+		target       string
+		variablePath string
+	}
+	capabilities := map[permissionKey][]string{}
+	capabilitySources := map[permissionKey]map[string][]string{}
+
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rules, err := r.policy(name)
+		if err != nil {
+			return nil, err
+		}
+		if rules == nil {
+			continue
+		}
+		for _, capability := range rules.Capabilities() {
+			key := permissionKey{capability.Scope, capability.Target, capability.VariablePath}
+			capabilities[key] = append(capabilities[key], capability.Capability)
+			if capabilitySources[key] == nil {
+				capabilitySources[key] = map[string][]string{}
+			}
+			capabilitySources[key][capability.Capability] = append(capabilitySources[key][capability.Capability], sources[name]...)
+		}
+	}
+
+	keys := make([]permissionKey, 0, len(capabilities))
+	for key := range capabilities {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].resourceType != keys[j].resourceType {
+			return keys[i].resourceType < keys[j].resourceType
+		}
+		if keys[i].target != keys[j].target {
+			return keys[i].target < keys[j].target
+		}
+		return keys[i].variablePath < keys[j].variablePath
+	})
+
+	var permissions []aclTokenEffectivePermissionInfo
+	for _, key := range keys {
+		for _, capability := range mergeCapabilities(capabilities[key]) {
+			permissions = append(permissions, aclTokenEffectivePermissionInfo{
+				ResourceType: key.resourceType,
+				Target:       key.target,
+				VariablePath: key.variablePath,
+				Capability:   capability,
+				Sources:      capabilitySources[key][capability],
+			})
+		}
+	}
+
+	return permissions, nil
+}
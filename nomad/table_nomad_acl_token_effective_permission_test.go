@@ -0,0 +1,120 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func TestListACLTokenEffectivePermissions(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/tokens", []*api.ACLTokenListStub{
+		{AccessorID: "token-1", Name: "ci", Type: "client", Policies: []string{"readonly"}, Roles: []*api.ACLTokenRoleLink{{ID: "role-1"}, {ID: "role-gone"}}},
+		{AccessorID: "token-2", Name: "bootstrap", Type: "management"},
+	})
+	f.handle("/v1/acl/token/token-1", &api.ACLToken{AccessorID: "token-1", Name: "ci", Type: "client", Policies: []string{"readonly"}, Roles: []*api.ACLTokenRoleLink{{ID: "role-1"}, {ID: "role-gone"}}})
+	f.handle("/v1/acl/role/role-1", &api.ACLRole{ID: "role-1", Name: "operators", Policies: []*api.ACLRolePolicyLink{{Name: "ops"}, {Name: "policy-gone"}}})
+	f.handle("/v1/acl/policy/readonly", &api.ACLPolicy{Name: "readonly", Rules: testACLPolicyRules})
+	f.handle("/v1/acl/policy/ops", &api.ACLPolicy{Name: "ops", Rules: `node { policy = "write" }`})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_acl_token_effective_permission",
+		columns: []string{"accessor_id", "management", "resource_type", "target", "capability", "sources"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"accessor_id": stringQual("token-1")}),
+	}.mustExecute(t, server)
+
+	capabilities := map[string]string{}
+	for _, row := range rows {
+		if row.string("accessor_id") != "token-1" || row.bool("management") {
+			t.Errorf("unexpected row: %v", row)
+		}
+		capabilities[row.string("resource_type")+"/"+row.string("target")+"/"+row.string("capability")] = row.json("sources")
+	}
+	// The node write capability of the role supersedes the read capability of
+	// the policy attached to the token
+	if sources, ok := capabilities["node//write"]; !ok || sources != `["operators/ops"]` {
+		t.Errorf("got sources %q for the node write capability, want the role policy", sources)
+	}
+	if _, ok := capabilities["namespace/default/read-job"]; !ok {
+		t.Errorf("missing the read-job capability in %v", capabilities)
+	}
+	if len(f.requestsTo("/v1/acl/tokens")) != 0 {
+		t.Errorf("expected the token to be fetched by its accessor ID rather than listed")
+	}
+}
+
+func TestListACLTokenEffectivePermissionsInvalidPolicy(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/tokens", []*api.ACLTokenListStub{
+		{AccessorID: "token-1", Name: "ci", Type: "client", Policies: []string{"broken", "ops"}},
+		{AccessorID: "token-2", Name: "deploy", Type: "client", Policies: []string{"broken"}},
+	})
+	f.handle("/v1/acl/policy/broken", &api.ACLPolicy{Name: "broken", Rules: `namespace "default" {`})
+	f.handle("/v1/acl/policy/ops", &api.ACLPolicy{Name: "ops", Rules: `node { policy = "write" }`})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_acl_token_effective_permission",
+		columns: []string{"accessor_id", "resource_type", "sources"},
+	}.mustExecute(t, server)
+
+	// The broken policy grants nothing and the other policies still apply
+	if len(rows) == 0 {
+		t.Fatalf("got no rows, want the permissions of the ops policy")
+	}
+	for _, row := range rows {
+		if row.string("accessor_id") != "token-1" || row.string("resource_type") != "node" || row.json("sources") != `["ops"]` {
+			t.Errorf("unexpected row: %v", row)
+		}
+	}
+	if requests := f.requestsTo("/v1/acl/policy/broken"); len(requests) != 1 {
+		t.Errorf("got %d requests to the broken policy, want 1", len(requests))
+	}
+}
+
+func TestListACLTokenEffectivePermissionsFetchesRolesAndPoliciesOnce(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/tokens", []*api.ACLTokenListStub{
+		{AccessorID: "token-1", Name: "ci", Type: "client", Policies: []string{"readonly"}, Roles: []*api.ACLTokenRoleLink{{ID: "role-1"}, {ID: "role-gone"}}},
+		{AccessorID: "token-2", Name: "deploy", Type: "client", Policies: []string{"readonly", "policy-gone"}, Roles: []*api.ACLTokenRoleLink{{ID: "role-1"}, {ID: "role-gone"}}},
+		{AccessorID: "token-3", Name: "audit", Type: "client", Policies: []string{"ops", "policy-gone"}},
+	})
+	f.handle("/v1/acl/role/role-1", &api.ACLRole{ID: "role-1", Name: "operators", Policies: []*api.ACLRolePolicyLink{{Name: "ops"}}})
+	f.handle("/v1/acl/policy/readonly", &api.ACLPolicy{Name: "readonly", Rules: testACLPolicyRules})
+	f.handle("/v1/acl/policy/ops", &api.ACLPolicy{Name: "ops", Rules: `node { policy = "write" }`})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_acl_token_effective_permission",
+		columns: []string{"accessor_id", "capability"},
+	}.mustExecute(t, server)
+
+	tokens := map[string]bool{}
+	for _, row := range rows {
+		tokens[row.string("accessor_id")] = true
+	}
+	if len(tokens) != 3 {
+		t.Errorf("got permissions for tokens %v, want 3 tokens", tokens)
+	}
+	for _, path := range []string{"/v1/acl/role/role-1", "/v1/acl/role/role-gone", "/v1/acl/policy/readonly", "/v1/acl/policy/ops", "/v1/acl/policy/policy-gone"} {
+		if requests := f.requestsTo(path); len(requests) != 1 {
+			t.Errorf("got %d requests to %s, want 1", len(requests), path)
+		}
+	}
+}
+
+func TestListACLTokenEffectivePermissionsManagement(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/tokens", []*api.ACLTokenListStub{{AccessorID: "token-2", Name: "bootstrap", Type: "management"}})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_acl_token_effective_permission",
+		columns: []string{"accessor_id", "management", "capability"},
+	}.mustExecute(t, server)
+	if len(rows) != 1 || !rows[0].bool("management") || rows[0].string("capability") != "*" {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+}
@@ -0,0 +1,222 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+// ACL token finding types
+const (
+	aclTokenFindingMissingPolicy     = "missing_policy"
+	aclTokenFindingMissingRole       = "missing_role"
+	aclTokenFindingNoExpiration      = "no_expiration"
+	aclTokenFindingManagement        = "management"
+	aclTokenFindingExpiredButPresent = "expired_but_present"
+)
+
+// ACL token finding severities
+const (
+	aclTokenFindingSeverityHigh   = "high"
+	aclTokenFindingSeverityMedium = "medium"
+	aclTokenFindingSeverityLow    = "low"
+)
+
+type aclTokenFindingInfo struct {
+	AccessorID     string
+	TokenName      string
+	TokenType      string
+	FindingType    string
+	Severity       string
+	Reference      string
+	Detail         string
+	ExpirationTime *time.Time
+}
+
+func tableNomadACLTokenFinding(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_acl_token_finding",
+		Description: "Retrieve hygiene findings for your ACL tokens, such as references to missing policies or roles, and tokens that never expire.",
+		List: &plugin.ListConfig{
+			Hydrate: listACLTokenFindings,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "accessor_id",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "finding_type",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "accessor_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The accessor ID of the acl token.",
+				Transform:   transform.FromField("AccessorID"),
+			},
+			{
+				Name:        "token_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the acl token.",
+			},
+			{
+				Name:        "token_type",
+				Type:        proto.ColumnType_STRING,
+				Description: "The type of the acl token, either client or management.",
+			},
+			{
+				Name:        "finding_type",
+				Type:        proto.ColumnType_STRING,
+				Description: "The type of the finding, one of missing_policy, missing_role, no_expiration, management or expired_but_present.",
+			},
+			{
+				Name:        "severity",
+				Type:        proto.ColumnType_STRING,
+				Description: "The severity of the finding, one of high, medium or low.",
+			},
+			{
+				Name:        "reference",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the missing policy or the ID of the missing role the finding refers to.",
+				Transform:   transform.FromField("Reference").NullIfZero(),
+			},
+			{
+				Name:        "detail",
+				Type:        proto.ColumnType_STRING,
+				Description: "A human readable description of the finding.",
+			},
+			{
+				Name:        "expiration_time",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The expiration time of the acl token.",
+			},
+
+			/// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "The title of the acl token finding.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Detail"),
+			},
+		},
+	}
+}
+
+func listACLTokenFindings(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	client, err := getClient(ctx, d)
+	if err != nil {
+		logger.Error("nomad_acl_token_finding.listACLTokenFindings", "connection_error", err)
+		return nil, err
+	}
+
+	policies, err := listACLPolicyNames(ctx, d, client)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := listACLRoleIDs(ctx, d, client)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	streamFindings := func(token *api.ACLTokenListStub) (bool, error) {
+		if d.EqualsQualString("accessor_id") != "" && d.EqualsQualString("accessor_id") != token.AccessorID {
+			return true, nil
+		}
+		for _, finding := range aclTokenFindings(token, policies, roles, now) {
+			if d.EqualsQualString("finding_type") != "" && d.EqualsQualString("finding_type") != finding.FindingType {
+				continue
+			}
+			d.StreamListItem(ctx, finding)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+
+	if err := listPages(ctx, d, "nomad_acl_token_finding.listACLTokenFindings", "ACL tokens", queryOptions(d), client.ACLTokens().List, streamFindings); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// aclTokenFindings returns the hygiene findings of a token, given the names of
+// the existing policies and the IDs of the existing roles.
+func aclTokenFindings(token *api.ACLTokenListStub, policies, roles map[string]bool, now time.Time) []aclTokenFindingInfo {
+	var findings []aclTokenFindingInfo
+	newFinding := func(findingType, severity, reference, detail string) {
+		findings = append(findings, aclTokenFindingInfo{
+			AccessorID:     token.AccessorID,
+			TokenName:      token.Name,
+			TokenType:      token.Type,
+			FindingType:    findingType,
+			Severity:       severity,
+			Reference:      reference,
+			Detail:         detail,
+			ExpirationTime: token.ExpirationTime,
+		})
+	}
+
+	if token.Type == aclTokenTypeManagement {
+		newFinding(aclTokenFindingManagement, aclTokenFindingSeverityHigh, "", "The token is a management token and is granted every permission.")
+	}
+	for _, policy := range token.Policies {
+		if !policies[policy] {
+			newFinding(aclTokenFindingMissingPolicy, aclTokenFindingSeverityMedium, policy, fmt.Sprintf("The token references the policy %q, which does not exist.", policy))
+		}
+	}
+	for _, role := range token.Roles {
+		if role != nil && !roles[role.ID] {
+			newFinding(aclTokenFindingMissingRole, aclTokenFindingSeverityMedium, role.ID, fmt.Sprintf("The token references the role %q (%s), which does not exist.", role.Name, role.ID))
+		}
+	}
+	if token.ExpirationTime == nil {
+		newFinding(aclTokenFindingNoExpiration, aclTokenFindingSeverityLow, "", "The token has no expiration time.")
+	} else if token.ExpirationTime.Before(now) {
+		newFinding(aclTokenFindingExpiredButPresent, aclTokenFindingSeverityLow, "", fmt.Sprintf("The token expired at %s but has not been garbage collected yet.", token.ExpirationTime.Format(time.RFC3339)))
+	}
+
+	return findings
+}
+
+// listACLPolicyNames returns the set of names of the existing ACL policies.
+func listACLPolicyNames(ctx context.Context, d *plugin.QueryData, client *api.Client) (map[string]bool, error) {
+	names := map[string]bool{}
+	err := listPages(ctx, d, "nomad_acl_token_finding.listACLPolicyNames", "ACL policies", queryOptions(d), client.ACLPolicies().List, func(policy *api.ACLPolicyListStub) (bool, error) {
+		names[policy.Name] = true
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// listACLRoleIDs returns the set of IDs of the existing ACL roles.
+func listACLRoleIDs(ctx context.Context, d *plugin.QueryData, client *api.Client) (map[string]bool, error) {
+	ids := map[string]bool{}
+	err := listPages(ctx, d, "nomad_acl_token_finding.listACLRoleIDs", "ACL roles", queryOptions(d), client.ACLRoles().List, func(role *api.ACLRoleListStub) (bool, error) {
+		ids[role.ID] = true
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
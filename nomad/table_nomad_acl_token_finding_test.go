@@ -0,0 +1,67 @@
+package nomad
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func TestListACLTokenFindings(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/policies", []*api.ACLPolicyListStub{{Name: "readonly"}})
+	f.handle("/v1/acl/roles", []*api.ACLRoleListStub{{ID: "role-1", Name: "operators"}})
+	f.handle("/v1/acl/tokens", []*api.ACLTokenListStub{
+		{AccessorID: "token-1", Name: "ci", Type: "client", Policies: []string{"readonly", "policy-gone"}, Roles: []*api.ACLTokenRoleLink{{ID: "role-1"}, {ID: "role-gone", Name: "gone"}}},
+		{AccessorID: "token-2", Name: "bootstrap", Type: "management", ExpirationTime: &expired},
+	})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_acl_token_finding",
+		columns: []string{"accessor_id", "finding_type", "severity", "reference"},
+	}.mustExecute(t, server)
+
+	var findings []string
+	for _, row := range rows {
+		findings = append(findings, strings.Join([]string{row.string("accessor_id"), row.string("finding_type"), row.string("severity"), row.string("reference")}, "/"))
+	}
+	sort.Strings(findings)
+	want := []string{
+		"token-1/missing_policy/medium/policy-gone",
+		"token-1/missing_role/medium/role-gone",
+		"token-1/no_expiration/low/",
+		"token-2/expired_but_present/low/",
+		"token-2/management/high/",
+	}
+	if strings.Join(findings, ",") != strings.Join(want, ",") {
+		t.Errorf("got findings %v, want %v", findings, want)
+	}
+}
+
+func TestListACLTokenFindingsFilters(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/policies", []*api.ACLPolicyListStub{})
+	f.handle("/v1/acl/roles", []*api.ACLRoleListStub{})
+	f.handle("/v1/acl/tokens", []*api.ACLTokenListStub{
+		{AccessorID: "token-1", Type: "client", Policies: []string{"policy-gone"}},
+		{AccessorID: "token-2", Type: "management"},
+	})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_acl_token_finding",
+		columns: []string{"accessor_id", "finding_type"},
+		quals: equalsQuals(map[string]*proto.QualValue{
+			"accessor_id":  stringQual("token-1"),
+			"finding_type": stringQual("no_expiration"),
+		}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].string("accessor_id") != "token-1" || rows[0].string("finding_type") != "no_expiration" {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+}
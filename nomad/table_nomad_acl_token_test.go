@@ -0,0 +1,75 @@
+package nomad
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func TestListACLTokens(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handlePages("/v1/acl/tokens",
+		[]*api.ACLTokenListStub{{AccessorID: "token-1", Name: "ci", Type: "client", Policies: []string{"readonly"}}},
+		[]*api.ACLTokenListStub{{AccessorID: "token-2", Name: "bootstrap", Type: "management", Global: true}},
+	)
+	f.handle("/v1/acl/token/token-1", &api.ACLToken{AccessorID: "token-1", SecretID: "secret-1", Name: "ci", Type: "client"})
+	f.handle("/v1/acl/token/token-2", &api.ACLToken{AccessorID: "token-2", SecretID: "secret-2", Name: "bootstrap", Type: "management"})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{table: "nomad_acl_token", columns: []string{"accessor_id", "secret_id", "name", "type", "global", "policies"}}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 rows across both pages", len(rows))
+	}
+	for _, row := range rows {
+		if !strings.HasPrefix(row.string("secret_id"), redactedSecretPrefix) {
+			t.Errorf("got secret ID %q, want it redacted", row.string("secret_id"))
+		}
+		// The fingerprint must not be confirmable by hashing a guessed secret
+		unkeyed := sha256.Sum256([]byte("secret-1"))
+		if strings.Contains(row.string("secret_id"), hex.EncodeToString(unkeyed[:8])) {
+			t.Errorf("got secret ID %q, want a keyed fingerprint", row.string("secret_id"))
+		}
+		if row.string("accessor_id") == "token-2" && (row.string("type") != "management" || !row.bool("global")) {
+			t.Errorf("unexpected token-2 row: %v", row)
+		}
+	}
+}
+
+func TestListACLTokensPushesDownQuals(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/tokens", []*api.ACLTokenListStub{{AccessorID: "token-1", Name: "ci"}})
+	server := newTestPluginServer(t, f, "")
+
+	testQuery{
+		table:   "nomad_acl_token",
+		columns: []string{"accessor_id", "name"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"name": stringQual("ci")}),
+	}.mustExecute(t, server)
+
+	requests := f.requestsTo("/v1/acl/tokens")
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	if filter := strings.TrimSpace(requests[0].URL.Query().Get("filter")); filter != `Name== "ci"` {
+		t.Errorf("got filter %q, want the name filter", filter)
+	}
+}
+
+func TestGetACLTokenRevealSecrets(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/acl/token/token-1", &api.ACLToken{AccessorID: "token-1", SecretID: "secret-1", Name: "ci"})
+	server := newTestPluginServer(t, f, "reveal_secrets = true")
+
+	rows := testQuery{
+		table:   "nomad_acl_token",
+		columns: []string{"accessor_id", "secret_id"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"accessor_id": stringQual("token-1")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].string("secret_id") != "secret-1" {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+}
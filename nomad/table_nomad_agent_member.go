@@ -3,6 +3,7 @@ package nomad
 import (
 	"context"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
@@ -30,6 +31,7 @@ func tableNomadAgentMember(ctx context.Context) *plugin.Table {
 				Name:        "address",
 				Type:        proto.ColumnType_STRING,
 				Description: "The IP address or hostname of the agent member.",
+				Transform:   transform.FromField("Addr"),
 			},
 			{
 				Name:        "port",
@@ -90,19 +92,19 @@ func listAgentMembers(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrat
 		return nil, err
 	}
 
-	servers, err := client.Agent().Members()
-	if err != nil {
-		plugin.Logger(ctx).Error("nomad_agent_member.listAgentMembers", "api_error", err)
-		return nil, err
+	// The members endpoint is not paginated and returns every member at once
+	members := func(input *api.QueryOptions) ([]*api.AgentMember, *api.QueryMeta, error) {
+		servers, err := client.Agent().MembersOpts(input)
+		if err != nil {
+			return nil, nil, err
+		}
+		return servers.Members, &api.QueryMeta{}, nil
 	}
 
-	for _, member := range servers.Members {
-		d.StreamListItem(ctx, member)
-
-		// Context can be cancelled due to manual cancellation or the limit has been hit
-		if d.RowsRemaining(ctx) == 0 {
-			return nil, nil
-		}
+	if err := streamPages(ctx, d, "nomad_agent_member.listAgentMembers", "agent members", queryOptions(d), members, func(member *api.AgentMember, _ *api.QueryMeta) interface{} {
+		return member
+	}); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
@@ -0,0 +1,40 @@
+package nomad
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+func TestListAgentMembers(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/agent/members", &api.ServerMembers{
+		ServerName: "server-1.global",
+		Members: []*api.AgentMember{
+			{Name: "server-1.global", Addr: "10.0.0.1", Port: 4648, Status: "alive", Tags: map[string]string{"role": "nomad"}},
+			{Name: "server-2.global", Addr: "10.0.0.2", Port: 4648, Status: "failed"},
+		},
+	})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{table: "nomad_agent_member", columns: []string{"name", "status", "address", "port", "tags"}}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	for _, row := range rows {
+		if row.string("name") == "server-1.global" && (row.string("address") != "10.0.0.1" || row.int("port") != 4648 || row.json("tags") != `{"role":"nomad"}`) {
+			t.Errorf("unexpected server-1 row: %v", row)
+		}
+	}
+}
+
+func TestListAgentMembersError(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handleError("/v1/agent/members", http.StatusForbidden, "Permission denied")
+	server := newTestPluginServer(t, f, "")
+
+	if _, err := (testQuery{table: "nomad_agent_member", columns: []string{"name"}}).execute(t, server); err == nil {
+		t.Errorf("expected a permission error")
+	}
+}
@@ -0,0 +1,226 @@
+package nomad
+
+import (
+	"context"
+	"io"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+// maxFileContentBytes is the largest file whose content is returned
+const maxFileContentBytes = int64(64 * 1024)
+
+type allocationFileInfo struct {
+	AllocID     string
+	Path        string
+	FilePath    string
+	Name        string
+	IsDir       bool
+	Size        int64
+	FileMode    string
+	ModTime     time.Time
+	ContentType string
+	alloc       *api.Allocation
+}
+
+func tableNomadAllocationFile(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_allocation_file",
+		Description: "Retrieve information about the files in the directory of an allocation.",
+		List: &plugin.ListConfig{
+			Hydrate: listAllocationFiles,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "alloc_id",
+					Require: plugin.Required,
+				},
+				{
+					Name:    "path",
+					Require: plugin.Required,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "alloc_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the allocation.",
+				Transform:   transform.FromField("AllocID"),
+			},
+			{
+				Name:        "path",
+				Type:        proto.ColumnType_STRING,
+				Description: "The path that was browsed, relative to the allocation directory, e.g. alloc/logs or server/local.",
+			},
+			{
+				Name:        "file_path",
+				Type:        proto.ColumnType_STRING,
+				Description: "The path of the file, relative to the allocation directory.",
+			},
+			{
+				Name:        "name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the file.",
+			},
+			{
+				Name:        "is_dir",
+				Type:        proto.ColumnType_BOOL,
+				Description: "True if the file is a directory.",
+			},
+			{
+				Name:        "size",
+				Type:        proto.ColumnType_INT,
+				Description: "The size of the file in bytes.",
+			},
+			{
+				Name:        "mode",
+				Type:        proto.ColumnType_STRING,
+				Description: "The file mode and permission bits, e.g. -rw-r--r--.",
+				Transform:   transform.FromField("FileMode"),
+			},
+			{
+				Name:        "mod_time",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The time at which the file was last modified.",
+			},
+			{
+				Name:        "content_type",
+				Type:        proto.ColumnType_STRING,
+				Description: "The detected content type of the file.",
+			},
+			{
+				Name:        "content",
+				Type:        proto.ColumnType_STRING,
+				Description: "The content of the file. Only returned for text files of up to 64 KiB. The content of the files in the secrets directories of the tasks is redacted to a fingerprint unless reveal_secrets is set in the connection config.",
+				Hydrate:     getAllocationFileContent,
+				Transform:   transform.FromValue(),
+			},
+
+			/// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "The title of the allocation file.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Name"),
+			},
+		},
+	}
+}
+
+func listAllocationFiles(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	allocID := d.EqualsQualString("alloc_id")
+	filePath := d.EqualsQualString("path")
+
+	client, err := getClient(ctx, d)
+	if err != nil {
+		logger.Error("nomad_allocation_file.listAllocationFiles", "connection_error", err)
+		return nil, err
+	}
+
+	alloc, _, err := client.Allocations().Info(allocID, queryOptions(d))
+	if err != nil {
+		logger.Error("nomad_allocation_file.listAllocationFiles", "api_error", err)
+		return nil, err
+	}
+
+	file, _, err := client.AllocFS().Stat(alloc, filePath, &api.QueryOptions{})
+	if err != nil {
+		logger.Error("nomad_allocation_file.listAllocationFiles", "api_error", err)
+		return nil, err
+	}
+
+	// A file path returns the file itself
+	if !file.IsDir {
+		d.StreamListItem(ctx, newAllocationFileInfo(alloc, filePath, filePath, file))
+		return nil, nil
+	}
+
+	files, _, err := client.AllocFS().List(alloc, filePath, &api.QueryOptions{})
+	if err != nil {
+		logger.Error("nomad_allocation_file.listAllocationFiles", "api_error", err)
+		return nil, err
+	}
+
+	for _, file := range files {
+		d.StreamListItem(ctx, newAllocationFileInfo(alloc, filePath, path.Join(filePath, file.Name), file))
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func getAllocationFileContent(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	file := h.Item.(allocationFileInfo)
+
+	// Only small files are read, and directories have no content
+	if file.IsDir || file.Size > maxFileContentBytes {
+		return nil, nil
+	}
+
+	client, err := getClient(ctx, d)
+	if err != nil {
+		logger.Error("nomad_allocation_file.getAllocationFileContent", "connection_error", err)
+		return nil, err
+	}
+
+	reader, err := client.AllocFS().ReadAt(file.alloc, file.FilePath, 0, maxFileContentBytes, &api.QueryOptions{})
+	if err != nil {
+		logger.Error("nomad_allocation_file.getAllocationFileContent", "api_error", err)
+		return nil, err
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(io.LimitReader(reader, maxFileContentBytes))
+	if err != nil {
+		logger.Error("nomad_allocation_file.getAllocationFileContent", "api_error", err)
+		return nil, err
+	}
+
+	// The secrets directories of the tasks hold the tokens and rendered
+	// templates meant only for the task
+	if isTaskSecretsPath(file.FilePath) {
+		return redactSecret(d, string(content)), nil
+	}
+
+	// Binary content is not returned
+	if !utf8.Valid(content) {
+		return nil, nil
+	}
+
+	return string(content), nil
+}
+
+func newAllocationFileInfo(alloc *api.Allocation, browsedPath, filePath string, file *api.AllocFileInfo) allocationFileInfo {
+	return allocationFileInfo{
+		AllocID:     alloc.ID,
+		Path:        browsedPath,
+		FilePath:    filePath,
+		Name:        file.Name,
+		IsDir:       file.IsDir,
+		Size:        file.Size,
+		FileMode:    file.FileMode,
+		ModTime:     file.ModTime,
+		ContentType: file.ContentType,
+		alloc:       alloc,
+	}
+}
+
+// isTaskSecretsPath returns true if the path, relative to the allocation
+// directory, is in the secrets directory of a task, e.g. web/secrets/token.
+func isTaskSecretsPath(filePath string) bool {
+	parts := strings.Split(strings.TrimPrefix(path.Clean("/"+filePath), "/"), "/")
+	return len(parts) >= 2 && parts[0] != "alloc" && parts[1] == "secrets"
+}
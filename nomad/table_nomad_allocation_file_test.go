@@ -0,0 +1,106 @@
+package nomad
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func TestListAllocationFiles(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/allocation/alloc-1", &api.Allocation{ID: "alloc-1", Name: "web.web[0]", NodeID: "node-1"})
+	f.handle("/v1/client/fs/stat/alloc-1", &api.AllocFileInfo{Name: "local", IsDir: true})
+	f.handle("/v1/client/fs/ls/alloc-1", []*api.AllocFileInfo{
+		{Name: "app.conf", Size: 11, FileMode: "-rw-r--r--"},
+		{Name: "data", IsDir: true, FileMode: "drwxr-xr-x"},
+	})
+	f.handleRaw("/v1/client/fs/readat/alloc-1", "port = 8080")
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_allocation_file",
+		columns: []string{"alloc_id", "path", "file_path", "name", "is_dir", "size", "content"},
+		quals: equalsQuals(map[string]*proto.QualValue{
+			"alloc_id": stringQual("alloc-1"),
+			"path":     stringQual("web/local"),
+		}),
+	}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	for _, row := range rows {
+		switch row.string("name") {
+		case "app.conf":
+			if row.string("file_path") != "web/local/app.conf" || row.int("size") != 11 || row.string("content") != "port = 8080" {
+				t.Errorf("unexpected app.conf row: %v", row)
+			}
+		case "data":
+			if !row.bool("is_dir") || !row.isNull("content") {
+				t.Errorf("unexpected data row: %v", row)
+			}
+		}
+	}
+
+	if requests := f.requestsTo("/v1/client/fs/ls/alloc-1"); len(requests) != 1 || requests[0].URL.Query().Get("path") != "web/local" {
+		t.Errorf("expected the directory to be listed at the requested path")
+	}
+	if requests := f.requestsTo("/v1/client/fs/readat/alloc-1"); len(requests) != 1 || requests[0].URL.Query().Get("path") != "web/local/app.conf" {
+		t.Errorf("expected only the content of the file to be read")
+	}
+}
+
+func TestAllocationFileSecretsContent(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config   string
+		redacted bool
+	}{
+		"redacted": {redacted: true},
+		"revealed": {config: "reveal_secrets = true"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			f := newFakeNomad(t)
+			f.handle("/v1/allocation/alloc-1", &api.Allocation{ID: "alloc-1", NodeID: "node-1"})
+			f.handle("/v1/client/fs/stat/alloc-1", &api.AllocFileInfo{Name: "vault_token", Size: 8})
+			f.handleRaw("/v1/client/fs/readat/alloc-1", "s.hunter")
+			server := newTestPluginServer(t, f, tc.config)
+
+			rows := testQuery{
+				table:   "nomad_allocation_file",
+				columns: []string{"name", "content"},
+				quals: equalsQuals(map[string]*proto.QualValue{
+					"alloc_id": stringQual("alloc-1"),
+					"path":     stringQual("web/secrets/vault_token"),
+				}),
+			}.mustExecute(t, server)
+			if len(rows) != 1 {
+				t.Fatalf("got %d rows, want 1", len(rows))
+			}
+			content := rows[0].string("content")
+			if tc.redacted && (content == "s.hunter" || !strings.HasPrefix(content, redactedSecretPrefix)) {
+				t.Errorf("got content %q, want it redacted", content)
+			}
+			if !tc.redacted && content != "s.hunter" {
+				t.Errorf("got content %q, want it revealed", content)
+			}
+		})
+	}
+}
+
+func TestIsTaskSecretsPath(t *testing.T) {
+	for filePath, want := range map[string]bool{
+		"web/secrets":                 true,
+		"web/secrets/token":           true,
+		"/web/secrets/env.txt":        true,
+		"./web//secrets/../secrets/a": true,
+		"web/local/secrets":           false,
+		"alloc/secrets":               false,
+		"secrets":                     false,
+		"web/local/app.conf":          false,
+	} {
+		if got := isTaskSecretsPath(filePath); got != want {
+			t.Errorf("isTaskSecretsPath(%q) = %v, want %v", filePath, got, want)
+		}
+	}
+}
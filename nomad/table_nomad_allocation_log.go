@@ -0,0 +1,243 @@
+package nomad
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+const (
+	// defaultLogMaxBytes is the number of log bytes read when max_bytes is not set
+	defaultLogMaxBytes = int64(64 * 1024)
+	// maxLogMaxBytes is the upper bound for max_bytes, so a query can never
+	// read an unbounded amount of log data
+	maxLogMaxBytes = int64(1024 * 1024)
+)
+
+type allocationLogLine struct {
+	AllocID    string
+	Task       string
+	LogType    string
+	Origin     string
+	Offset     int64
+	MaxBytes   int64
+	LineNumber int
+	Line       string
+}
+
+func tableNomadAllocationLog(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_allocation_log",
+		Description: "Retrieve a bounded window of the stdout or stderr logs of an allocation task.",
+		List: &plugin.ListConfig{
+			Hydrate: listAllocationLogs,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "alloc_id",
+					Require: plugin.Required,
+				},
+				{
+					Name:    "task",
+					Require: plugin.Required,
+				},
+				{
+					Name:    "log_type",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "origin",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "offset",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "max_bytes",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "alloc_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the allocation.",
+				Transform:   transform.FromField("AllocID"),
+			},
+			{
+				Name:        "task",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the task whose logs are read.",
+			},
+			{
+				Name:        "log_type",
+				Type:        proto.ColumnType_STRING,
+				Description: "The log stream that is read, either stdout or stderr. Defaults to stdout.",
+			},
+			{
+				Name:        "origin",
+				Type:        proto.ColumnType_STRING,
+				Description: "The position the offset is applied from, either start or end. Defaults to end.",
+			},
+			{
+				Name:        "offset",
+				Type:        proto.ColumnType_INT,
+				Description: "The offset in bytes from the origin at which reading starts. Defaults to max_bytes when the origin is end, and 0 otherwise.",
+			},
+			{
+				Name:        "max_bytes",
+				Type:        proto.ColumnType_INT,
+				Description: "The maximum number of bytes read from the log. Defaults to 65536 and is capped at 1048576.",
+			},
+			{
+				Name:        "line_number",
+				Type:        proto.ColumnType_INT,
+				Description: "The number of the line within the returned window, starting at 1.",
+			},
+			{
+				Name:        "line",
+				Type:        proto.ColumnType_STRING,
+				Description: "The content of the log line.",
+			},
+		},
+	}
+}
+
+func listAllocationLogs(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	allocID := d.EqualsQualString("alloc_id")
+	task := d.EqualsQualString("task")
+
+	logType := api.FSLogNameStdout
+	if d.EqualsQualString("log_type") != "" {
+		logType = d.EqualsQualString("log_type")
+	}
+	if logType != api.FSLogNameStdout && logType != api.FSLogNameStderr {
+		return nil, fmt.Errorf("log_type must be either %q or %q", api.FSLogNameStdout, api.FSLogNameStderr)
+	}
+
+	origin := api.OriginEnd
+	if d.EqualsQualString("origin") != "" {
+		origin = d.EqualsQualString("origin")
+	}
+	if origin != api.OriginStart && origin != api.OriginEnd {
+		return nil, fmt.Errorf("origin must be either %q or %q", api.OriginStart, api.OriginEnd)
+	}
+
+	maxBytes := defaultLogMaxBytes
+	if d.EqualsQuals["max_bytes"] != nil {
+		maxBytes = d.EqualsQuals["max_bytes"].GetInt64Value()
+	}
+	if maxBytes <= 0 || maxBytes > maxLogMaxBytes {
+		return nil, fmt.Errorf("max_bytes must be between 1 and %d", maxLogMaxBytes)
+	}
+
+	// Read the tail of the log by default
+	offset := int64(0)
+	if origin == api.OriginEnd {
+		offset = maxBytes
+	}
+	if d.EqualsQuals["offset"] != nil {
+		offset = d.EqualsQuals["offset"].GetInt64Value()
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative")
+	}
+
+	client, err := getClient(ctx, d)
+	if err != nil {
+		logger.Error("nomad_allocation_log.listAllocationLogs", "connection_error", err)
+		return nil, err
+	}
+
+	alloc, _, err := client.Allocations().Info(allocID, queryOptions(d))
+	if err != nil {
+		logger.Error("nomad_allocation_log.listAllocationLogs", "api_error", err)
+		return nil, err
+	}
+
+	data, err := readAllocationLog(ctx, client, alloc, task, logType, origin, offset, maxBytes)
+	if err != nil {
+		logger.Error("nomad_allocation_log.listAllocationLogs", "api_error", err)
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(data) == 0 {
+		lines = nil
+	}
+	for i, line := range lines {
+		d.StreamListItem(ctx, allocationLogLine{
+			AllocID:    allocID,
+			Task:       task,
+			LogType:    logType,
+			Origin:     origin,
+			Offset:     offset,
+			MaxBytes:   maxBytes,
+			LineNumber: i + 1,
+			Line:       strings.TrimSuffix(line, "\r"),
+		})
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// readAllocationLog reads at most maxBytes of the task log without following
+// it, and stops the stream as soon as the bound is reached.
+func readAllocationLog(ctx context.Context, client *api.Client, alloc *api.Allocation, task, logType, origin string, offset, maxBytes int64) ([]byte, error) {
+	cancel := make(chan struct{})
+	defer close(cancel)
+
+	frames, errCh := client.AllocFS().Logs(alloc, false, task, logType, origin, offset, cancel, &api.QueryOptions{})
+
+	var buf bytes.Buffer
+	for int64(buf.Len()) < maxBytes {
+		select {
+		case <-ctx.Done():
+			drainLogFrames(frames, errCh)
+			return nil, ctx.Err()
+		case err := <-errCh:
+			if err != nil {
+				return nil, err
+			}
+		case frame, ok := <-frames:
+			if !ok {
+				return buf.Bytes(), nil
+			}
+			buf.Write(frame.Data)
+		}
+	}
+
+	drainLogFrames(frames, errCh)
+	return buf.Bytes()[:maxBytes], nil
+}
+
+// drainLogFrames discards the remaining frames in the background so the
+// streaming goroutine is not blocked once reading has stopped early.
+func drainLogFrames(frames <-chan *api.StreamFrame, errCh <-chan error) {
+	go func() {
+		for {
+			select {
+			case _, ok := <-frames:
+				if !ok {
+					return
+				}
+			case <-errCh:
+				return
+			}
+		}
+	}()
+}
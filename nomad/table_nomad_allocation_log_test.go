@@ -0,0 +1,69 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func TestListAllocationLogs(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/allocation/alloc-1", &api.Allocation{ID: "alloc-1", NodeID: "node-1"})
+	f.handle("/v1/client/fs/logs/alloc-1", &api.StreamFrame{Data: []byte("starting\r\nlistening on :8080\n")})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_allocation_log",
+		columns: []string{"alloc_id", "task", "log_type", "origin", "line_number", "line"},
+		quals: equalsQuals(map[string]*proto.QualValue{
+			"alloc_id": stringQual("alloc-1"),
+			"task":     stringQual("web"),
+			"log_type": stringQual("stderr"),
+		}),
+	}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	for _, row := range rows {
+		if row.int("line_number") == 2 && row.string("line") != "listening on :8080" {
+			t.Errorf("got line %q", row.string("line"))
+		}
+		if row.int("line_number") == 1 && row.string("line") != "starting" {
+			t.Errorf("got line %q, want the carriage return trimmed", row.string("line"))
+		}
+		if row.string("log_type") != "stderr" || row.string("origin") != "end" {
+			t.Errorf("unexpected row: %v", row)
+		}
+	}
+
+	requests := f.requestsTo("/v1/client/fs/logs/alloc-1")
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	query := requests[0].URL.Query()
+	if query.Get("task") != "web" || query.Get("type") != "stderr" || query.Get("follow") != "false" || query.Get("origin") != "end" {
+		t.Errorf("unexpected log request: %s", requests[0].URL)
+	}
+}
+
+func TestListAllocationLogsInvalidQuals(t *testing.T) {
+	f := newFakeNomad(t)
+	server := newTestPluginServer(t, f, "")
+
+	_, err := testQuery{
+		table:   "nomad_allocation_log",
+		columns: []string{"line"},
+		quals: equalsQuals(map[string]*proto.QualValue{
+			"alloc_id": stringQual("alloc-1"),
+			"task":     stringQual("web"),
+			"log_type": stringQual("stdin"),
+		}),
+	}.execute(t, server)
+	if err == nil {
+		t.Errorf("expected an error for an invalid log type")
+	}
+	if len(f.requestsTo("/v1/allocation/alloc-1")) != 0 {
+		t.Errorf("expected the quals to be validated before calling the API")
+	}
+}
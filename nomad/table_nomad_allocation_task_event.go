@@ -0,0 +1,174 @@
+package nomad
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+type allocationTaskEventInfo struct {
+	AllocID        string
+	AllocName      string
+	Namespace      string
+	JobID          string
+	TaskGroup      string
+	NodeID         string
+	NodeName       string
+	Task           string
+	Type           string
+	Time           int64
+	DisplayMessage string
+	Message        string
+	FailsTask      bool
+	RestartReason  string
+	ExitCode       int
+	Signal         int
+	KillReason     string
+	KillError      string
+	DriverError    string
+	SetupError     string
+	DownloadError  string
+	Details        map[string]string
+}
+
+func tableNomadAllocationTaskEvent(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_allocation_task_event",
+		Description: "Retrieve information about the events of the tasks of your allocations.",
+		List: &plugin.ListConfig{
+			Hydrate:    listAllocationTaskEvents,
+			KeyColumns: allocationTaskKeyColumns(),
+		},
+		Columns: allocationTaskColumns([]*plugin.Column{
+			{
+				Name:        "type",
+				Type:        proto.ColumnType_STRING,
+				Description: "The type of the task event, e.g. Started, Terminated or Restarting.",
+			},
+			{
+				Name:        "time",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The time at which the task event occurred.",
+				Transform:   transform.FromField("Time").Transform(convertNanoSecToTimestamp),
+			},
+			{
+				Name:        "display_message",
+				Type:        proto.ColumnType_STRING,
+				Description: "A human readable message describing the task event.",
+			},
+			{
+				Name:        "message",
+				Type:        proto.ColumnType_STRING,
+				Description: "The raw message of the task event.",
+			},
+			{
+				Name:        "fails_task",
+				Type:        proto.ColumnType_BOOL,
+				Description: "True if the event marks the task as failed.",
+			},
+			{
+				Name:        "restart_reason",
+				Type:        proto.ColumnType_STRING,
+				Description: "The reason the task is being restarted.",
+			},
+			{
+				Name:        "exit_code",
+				Type:        proto.ColumnType_INT,
+				Description: "The exit code of the task.",
+			},
+			{
+				Name:        "signal",
+				Type:        proto.ColumnType_INT,
+				Description: "The signal that terminated the task.",
+			},
+			{
+				Name:        "kill_reason",
+				Type:        proto.ColumnType_STRING,
+				Description: "The reason the task was killed.",
+			},
+			{
+				Name:        "kill_error",
+				Type:        proto.ColumnType_STRING,
+				Description: "The error that occurred while killing the task.",
+			},
+			{
+				Name:        "driver_error",
+				Type:        proto.ColumnType_STRING,
+				Description: "The error returned by the task driver.",
+			},
+			{
+				Name:        "setup_error",
+				Type:        proto.ColumnType_STRING,
+				Description: "The error that occurred while setting up the task.",
+			},
+			{
+				Name:        "download_error",
+				Type:        proto.ColumnType_STRING,
+				Description: "The error that occurred while downloading the task artifacts.",
+			},
+			{
+				Name:        "details",
+				Type:        proto.ColumnType_JSON,
+				Description: "A map of additional details about the task event.",
+			},
+
+			/// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "The title of the allocation task event.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Type"),
+			},
+		}),
+	}
+}
+
+func listAllocationTaskEvents(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	err := listAllocationTasks(ctx, d, func(alloc allocationTasks) bool {
+		for _, task := range sortedTaskNames(alloc.TaskStates) {
+			for _, event := range alloc.TaskStates[task].Events {
+				if event == nil {
+					continue
+				}
+				d.StreamListItem(ctx, allocationTaskEventInfo{
+					AllocID:        alloc.AllocID,
+					AllocName:      alloc.AllocName,
+					Namespace:      alloc.Namespace,
+					JobID:          alloc.JobID,
+					TaskGroup:      alloc.TaskGroup,
+					NodeID:         alloc.NodeID,
+					NodeName:       alloc.NodeName,
+					Task:           task,
+					Type:           event.Type,
+					Time:           event.Time,
+					DisplayMessage: event.DisplayMessage,
+					Message:        event.Message,
+					FailsTask:      event.FailsTask,
+					RestartReason:  event.RestartReason,
+					ExitCode:       event.ExitCode,
+					Signal:         event.Signal,
+					KillReason:     event.KillReason,
+					KillError:      event.KillError,
+					DriverError:    event.DriverError,
+					SetupError:     event.SetupError,
+					DownloadError:  event.DownloadError,
+					Details:        event.Details,
+				})
+
+				// Context can be cancelled due to manual cancellation or the limit has been hit
+				if d.RowsRemaining(ctx) == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		plugin.Logger(ctx).Error("nomad_allocation_task_event.listAllocationTaskEvents", "api_error", err)
+		return nil, err
+	}
+
+	return nil, nil
+}
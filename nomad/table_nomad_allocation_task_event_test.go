@@ -0,0 +1,27 @@
+package nomad
+
+import (
+	"testing"
+)
+
+func TestListAllocationTaskEvents(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/allocations", testAllocationStubs())
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_allocation_task_event",
+		columns: []string{"alloc_id", "task", "type", "time", "display_message"},
+	}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want one per event", len(rows))
+	}
+	for _, row := range rows {
+		if row.string("alloc_id") != "alloc-1" || row.string("task") != "web" {
+			t.Errorf("unexpected row: %v", row)
+		}
+		if row.string("type") == "Started" && row["time"].GetTimestampValue().GetSeconds() != 1682000001 {
+			t.Errorf("got time %v, want the event time", row["time"].GetTimestampValue())
+		}
+	}
+}
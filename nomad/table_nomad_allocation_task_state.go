@@ -0,0 +1,274 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+// allocationTasks holds the allocation fields shared by the task tables.
+type allocationTasks struct {
+	AllocID    string
+	AllocName  string
+	Namespace  string
+	JobID      string
+	TaskGroup  string
+	NodeID     string
+	NodeName   string
+	TaskStates map[string]*api.TaskState
+}
+
+type allocationTaskStateInfo struct {
+	AllocID     string
+	AllocName   string
+	Namespace   string
+	JobID       string
+	TaskGroup   string
+	NodeID      string
+	NodeName    string
+	Task        string
+	State       string
+	Failed      bool
+	Restarts    uint64
+	LastRestart time.Time
+	StartedAt   time.Time
+	FinishedAt  time.Time
+}
+
+func tableNomadAllocationTaskState(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_allocation_task_state",
+		Description: "Retrieve information about the state of the tasks of your allocations.",
+		List: &plugin.ListConfig{
+			Hydrate:    listAllocationTaskStates,
+			KeyColumns: allocationTaskKeyColumns(),
+		},
+		Columns: allocationTaskColumns([]*plugin.Column{
+			{
+				Name:        "state",
+				Type:        proto.ColumnType_STRING,
+				Description: "The current state of the task, one of pending, running or dead.",
+			},
+			{
+				Name:        "failed",
+				Type:        proto.ColumnType_BOOL,
+				Description: "True if the task has failed.",
+			},
+			{
+				Name:        "restarts",
+				Type:        proto.ColumnType_INT,
+				Description: "The number of times the task has been restarted.",
+			},
+			{
+				Name:        "started_at",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The time at which the task was last started.",
+				Transform:   transform.FromField("StartedAt").Transform(zeroTimeToNil),
+			},
+			{
+				Name:        "finished_at",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The time at which the task finished.",
+				Transform:   transform.FromField("FinishedAt").Transform(zeroTimeToNil),
+			},
+			{
+				Name:        "last_restart",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The time at which the task was last restarted.",
+				Transform:   transform.FromField("LastRestart").Transform(zeroTimeToNil),
+			},
+
+			/// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "The title of the allocation task state.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Task"),
+			},
+		}),
+	}
+}
+
+// allocationTaskKeyColumns returns the key columns shared by the task tables.
+func allocationTaskKeyColumns() []*plugin.KeyColumn {
+	return []*plugin.KeyColumn{
+		{
+			Name:    "alloc_id",
+			Require: plugin.Optional,
+		},
+		{
+			Name:    "job_id",
+			Require: plugin.Optional,
+		},
+		{
+			Name:    "node_id",
+			Require: plugin.Optional,
+		},
+		{
+			Name:    "namespace",
+			Require: plugin.Optional,
+		},
+	}
+}
+
+// allocationTaskColumns prefixes the given columns with the allocation
+// columns shared by the task tables.
+func allocationTaskColumns(columns []*plugin.Column) []*plugin.Column {
+	return append([]*plugin.Column{
+		{
+			Name:        "alloc_id",
+			Type:        proto.ColumnType_STRING,
+			Description: "The ID of the allocation.",
+			Transform:   transform.FromField("AllocID"),
+		},
+		{
+			Name:        "alloc_name",
+			Type:        proto.ColumnType_STRING,
+			Description: "The name of the allocation.",
+		},
+		{
+			Name:        "task",
+			Type:        proto.ColumnType_STRING,
+			Description: "The name of the task.",
+		},
+		{
+			Name:        "namespace",
+			Type:        proto.ColumnType_STRING,
+			Description: "The namespace of the allocation.",
+		},
+		{
+			Name:        "job_id",
+			Type:        proto.ColumnType_STRING,
+			Description: "The ID of the job the allocation belongs to.",
+			Transform:   transform.FromField("JobID"),
+		},
+		{
+			Name:        "task_group",
+			Type:        proto.ColumnType_STRING,
+			Description: "The name of the task group the allocation belongs to.",
+		},
+		{
+			Name:        "node_id",
+			Type:        proto.ColumnType_STRING,
+			Description: "The ID of the node the allocation is placed on.",
+			Transform:   transform.FromField("NodeID"),
+		},
+		{
+			Name:        "node_name",
+			Type:        proto.ColumnType_STRING,
+			Description: "The name of the node the allocation is placed on.",
+		},
+	}, columns...)
+}
+
+func listAllocationTaskStates(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	err := listAllocationTasks(ctx, d, func(alloc allocationTasks) bool {
+		for _, task := range sortedTaskNames(alloc.TaskStates) {
+			state := alloc.TaskStates[task]
+			d.StreamListItem(ctx, allocationTaskStateInfo{
+				AllocID:     alloc.AllocID,
+				AllocName:   alloc.AllocName,
+				Namespace:   alloc.Namespace,
+				JobID:       alloc.JobID,
+				TaskGroup:   alloc.TaskGroup,
+				NodeID:      alloc.NodeID,
+				NodeName:    alloc.NodeName,
+				Task:        task,
+				State:       state.State,
+				Failed:      state.Failed,
+				Restarts:    state.Restarts,
+				LastRestart: state.LastRestart,
+				StartedAt:   state.StartedAt,
+				FinishedAt:  state.FinishedAt,
+			})
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		plugin.Logger(ctx).Error("nomad_allocation_task_state.listAllocationTaskStates", "api_error", err)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// listAllocationTasks calls fn for every allocation matching the alloc_id,
+// job_id, node_id and namespace quals until fn returns false.
+func listAllocationTasks(ctx context.Context, d *plugin.QueryData, fn func(allocationTasks) bool) error {
+	client, err := getClient(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("nomad_allocation_task_state.listAllocationTasks", "connection_error", err)
+		return err
+	}
+
+	input := queryOptions(d)
+	if d.EqualsQualString("namespace") != "" {
+		input.Namespace = d.EqualsQualString("namespace")
+	}
+
+	// Fetch the allocation directly if the ID has been provided
+	if d.EqualsQualString("alloc_id") != "" {
+		alloc, _, err := client.Allocations().Info(d.EqualsQualString("alloc_id"), input)
+		if err != nil {
+			return err
+		}
+		fn(allocationTasks{
+			AllocID:    alloc.ID,
+			AllocName:  alloc.Name,
+			Namespace:  alloc.Namespace,
+			JobID:      alloc.JobID,
+			TaskGroup:  alloc.TaskGroup,
+			NodeID:     alloc.NodeID,
+			NodeName:   alloc.NodeName,
+			TaskStates: alloc.TaskStates,
+		})
+		return nil
+	}
+
+	var filters []string
+	if d.EqualsQualString("job_id") != "" {
+		filters = append(filters, fmt.Sprintf("JobID == %q", d.EqualsQualString("job_id")))
+	}
+	if d.EqualsQualString("node_id") != "" {
+		filters = append(filters, fmt.Sprintf("NodeID == %q", d.EqualsQualString("node_id")))
+	}
+	input.Filter = strings.Join(filters, " and ")
+
+	return listPages(ctx, d, "nomad_allocation_task.listAllocationTasks", "allocations", input, client.Allocations().List, func(alloc *api.AllocationListStub) (bool, error) {
+		return fn(allocationTasks{
+			AllocID:    alloc.ID,
+			AllocName:  alloc.Name,
+			Namespace:  alloc.Namespace,
+			JobID:      alloc.JobID,
+			TaskGroup:  alloc.TaskGroup,
+			NodeID:     alloc.NodeID,
+			NodeName:   alloc.NodeName,
+			TaskStates: alloc.TaskStates,
+		}), nil
+	})
+}
+
+// sortedTaskNames returns the task names of the allocation in a stable order,
+// skipping tasks without a state.
+func sortedTaskNames(states map[string]*api.TaskState) []string {
+	names := make([]string, 0, len(states))
+	for name, state := range states {
+		if state != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,113 @@
+package nomad
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func testAllocationStubs() []*api.AllocationListStub {
+	return []*api.AllocationListStub{
+		{
+			ID:        "alloc-1",
+			Name:      "web.web[0]",
+			Namespace: "default",
+			JobID:     "web",
+			TaskGroup: "web",
+			NodeID:    "node-1",
+			NodeName:  "client-1",
+			TaskStates: map[string]*api.TaskState{
+				"web": {
+					State:    "running",
+					Restarts: 1,
+					Events: []*api.TaskEvent{
+						{Type: "Received", Time: 1682000000000000000, DisplayMessage: "Task received by client"},
+						{Type: "Started", Time: 1682000001000000000, DisplayMessage: "Task started by client"},
+					},
+				},
+				"sidecar": {State: "dead", Failed: true},
+			},
+		},
+	}
+}
+
+func TestListAllocationTaskStates(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handlePages("/v1/allocations",
+		testAllocationStubs(),
+		[]*api.AllocationListStub{{ID: "alloc-2", JobID: "api", TaskStates: map[string]*api.TaskState{"api": {State: "pending"}}}},
+	)
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_allocation_task_state",
+		columns: []string{"alloc_id", "job_id", "node_name", "task", "state", "failed", "restarts"},
+	}.mustExecute(t, server)
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want one per task across both pages", len(rows))
+	}
+	for _, row := range rows {
+		switch row.string("task") {
+		case "web":
+			if row.string("state") != "running" || row.int("restarts") != 1 || row.string("node_name") != "client-1" {
+				t.Errorf("unexpected web task row: %v", row)
+			}
+		case "sidecar":
+			if row.string("state") != "dead" || !row.bool("failed") {
+				t.Errorf("unexpected sidecar task row: %v", row)
+			}
+		}
+	}
+}
+
+func TestListAllocationTaskStatesPushesDownQuals(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/allocations", testAllocationStubs())
+	server := newTestPluginServer(t, f, "")
+
+	testQuery{
+		table:   "nomad_allocation_task_state",
+		columns: []string{"alloc_id", "task"},
+		quals: equalsQuals(map[string]*proto.QualValue{
+			"namespace": stringQual("default"),
+			"job_id":    stringQual("web"),
+			"node_id":   stringQual("node-1"),
+		}),
+	}.mustExecute(t, server)
+
+	requests := f.requestsTo("/v1/allocations")
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	query := requests[0].URL.Query()
+	if query.Get("namespace") != "default" {
+		t.Errorf("got namespace %q, want default", query.Get("namespace"))
+	}
+	if filter := query.Get("filter"); !strings.Contains(filter, `JobID == "web"`) || !strings.Contains(filter, `NodeID == "node-1"`) {
+		t.Errorf("got filter %q, want the job and node filters", filter)
+	}
+}
+
+func TestListAllocationTaskStatesByAllocation(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/allocation/alloc-1", &api.Allocation{
+		ID:         "alloc-1",
+		JobID:      "web",
+		TaskStates: map[string]*api.TaskState{"web": {State: "running"}},
+	})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_allocation_task_state",
+		columns: []string{"alloc_id", "task", "state"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"alloc_id": stringQual("alloc-1")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].string("state") != "running" {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+	if len(f.requestsTo("/v1/allocations")) != 0 {
+		t.Errorf("expected the allocation to be fetched without listing allocations")
+	}
+}
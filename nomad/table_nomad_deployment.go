@@ -10,6 +10,11 @@ import (
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
 )
 
+type deploymentRow struct {
+	api.Deployment
+	queryMetaRow
+}
+
 func tableNomadDeployment(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
 		Name:        "nomad_deployment",
@@ -29,13 +34,14 @@ func tableNomadDeployment(ctx context.Context) *plugin.Table {
 					Name:    "job_id",
 					Require: plugin.Optional,
 				},
+				consistencyKeyColumn(),
 			},
 		},
 		Get: &plugin.GetConfig{
-			KeyColumns: plugin.SingleColumn("id"),
+			KeyColumns: append(plugin.SingleColumn("id"), consistencyKeyColumn()),
 			Hydrate:    getDeployment,
 		},
-		Columns: []*plugin.Column{
+		Columns: queryMetaColumns([]*plugin.Column{
 			{
 				Name:        "id",
 				Type:        proto.ColumnType_STRING,
@@ -111,7 +117,7 @@ func tableNomadDeployment(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromField("ID"),
 			},
-		},
+		}),
 	}
 }
 
@@ -122,16 +128,8 @@ func listDeployments(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrate
 		return nil, err
 	}
 
-	maxLimit := int64(1000)
-	if d.QueryContext.Limit != nil {
-		if *d.QueryContext.Limit < maxLimit {
-			maxLimit = *d.QueryContext.Limit
-		}
-	}
-
-	input := &api.QueryOptions{
-		PerPage: int32(maxLimit),
-	}
+	input := queryOptions(d)
+	input.PerPage = pageSize(d, "namespace", "job_id")
 
 	if d.EqualsQualString("namespace") != "" {
 		input.Namespace = d.EqualsQualString("namespace")
@@ -144,25 +142,10 @@ func listDeployments(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrate
 		input.Filter = filter
 	}
 
-	for {
-		deployments, metadata, err := client.Deployments().List(input)
-		if err != nil {
-			plugin.Logger(ctx).Error("nomad_deployment.listDeployments", "api_error", err)
-			return nil, err
-		}
-
-		for _, deployment := range deployments {
-			d.StreamListItem(ctx, deployment)
-
-			// Context can be cancelled due to manual cancellation or the limit has been hit
-			if d.RowsRemaining(ctx) == 0 {
-				return nil, nil
-			}
-		}
-		input.NextToken = metadata.NextToken
-		if input.NextToken == "" {
-			break
-		}
+	if err := streamPages(ctx, d, "nomad_deployment.listDeployments", "deployments", input, client.Deployments().List, func(item *api.Deployment, meta *api.QueryMeta) interface{} {
+		return deploymentRow{Deployment: *item, queryMetaRow: queryMetaRow{meta: meta}}
+	}); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
@@ -184,11 +167,11 @@ func getDeployment(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDa
 		return nil, err
 	}
 
-	deployment, _, err := client.Deployments().Info(id, &api.QueryOptions{})
+	deployment, meta, err := client.Deployments().Info(id, queryOptions(d))
 	if err != nil {
 		logger.Error("nomad_node.getDeployment", "api_error", err)
 		return nil, err
 	}
 
-	return deployment, nil
+	return deploymentRow{Deployment: *deployment, queryMetaRow: queryMetaRow{meta: meta}}, nil
 }
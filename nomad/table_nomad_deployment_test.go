@@ -0,0 +1,77 @@
+package nomad
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func TestListDeployments(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handlePages("/v1/deployments",
+		[]*api.Deployment{{ID: "deploy-1", JobID: "web", Namespace: "default", Status: "successful", JobVersion: 2}},
+		[]*api.Deployment{{ID: "deploy-2", JobID: "api", Namespace: "default", Status: "running"}},
+	)
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{table: "nomad_deployment", columns: []string{"id", "job_id", "status", "job_version"}}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 rows across both pages", len(rows))
+	}
+	for _, row := range rows {
+		if row.string("id") == "deploy-1" && (row.string("job_id") != "web" || row.string("job_version") != "2") {
+			t.Errorf("unexpected deploy-1 row: %v", row)
+		}
+	}
+}
+
+func TestListDeploymentsPushesDownQuals(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/deployments", []*api.Deployment{{ID: "deploy-1", JobID: "web", Namespace: "apps"}})
+	server := newTestPluginServer(t, f, "")
+
+	testQuery{
+		table:   "nomad_deployment",
+		columns: []string{"id", "job_id", "namespace"},
+		quals: equalsQuals(map[string]*proto.QualValue{
+			"namespace": stringQual("apps"),
+			"job_id":    stringQual("web"),
+		}),
+	}.mustExecute(t, server)
+
+	requests := f.requestsTo("/v1/deployments")
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	query := requests[0].URL.Query()
+	if query.Get("namespace") != "apps" {
+		t.Errorf("got namespace %q, want apps", query.Get("namespace"))
+	}
+	if filter := strings.TrimSpace(query.Get("filter")); filter != `JobID== "web"` {
+		t.Errorf("got filter %q, want the job ID filter", filter)
+	}
+}
+
+func TestGetDeployment(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/deployment/deploy-1", &api.Deployment{
+		ID:     "deploy-1",
+		JobID:  "web",
+		Status: "successful",
+		TaskGroups: map[string]*api.DeploymentState{
+			"web": {DesiredTotal: 3, HealthyAllocs: 3},
+		},
+	})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_deployment",
+		columns: []string{"id", "job_id", "task_groups"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"id": stringQual("deploy-1")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].string("job_id") != "web" || !strings.Contains(rows[0].json("task_groups"), `"DesiredTotal":3`) {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+}
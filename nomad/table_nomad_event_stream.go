@@ -0,0 +1,249 @@
+package nomad
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+const (
+	// maxEventStreamDuration is the upper bound for duration, and the time after
+	// which the stream is closed when only max_events is set, so a query can
+	// never hold the event stream open indefinitely
+	maxEventStreamDuration = 10 * time.Minute
+	// maxEventStreamEvents is the upper bound for max_events
+	maxEventStreamEvents = int64(10000)
+)
+
+type eventStreamEvent struct {
+	Topic      string
+	Type       string
+	Key        string
+	Namespace  string
+	FilterKeys []string
+	FilterKey  string
+	Index      uint64
+	Payload    map[string]interface{}
+	Duration   string
+	MaxEvents  *int64
+}
+
+func tableNomadEventStream(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_event_stream",
+		Description: "Capture a bounded window of the events of the Nomad event stream.",
+		List: &plugin.ListConfig{
+			Hydrate: listEventStream,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "duration",
+					Require: plugin.AnyOf,
+				},
+				{
+					Name:    "max_events",
+					Require: plugin.AnyOf,
+				},
+				{
+					Name:    "topic",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "filter_key",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "namespace",
+					Require: plugin.Optional,
+				},
+				{
+					Name:      "index",
+					Require:   plugin.Optional,
+					Operators: []string{"=", ">", ">="},
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "topic",
+				Type:        proto.ColumnType_STRING,
+				Description: "The topic of the event, such as Job, Allocation, Evaluation, Deployment, Node or Service. Defaults to all topics.",
+			},
+			{
+				Name:        "type",
+				Type:        proto.ColumnType_STRING,
+				Description: "The type of the event, such as JobRegistered or AllocationUpdated.",
+			},
+			{
+				Name:        "key",
+				Type:        proto.ColumnType_STRING,
+				Description: "The key of the object the event is about, such as the ID of a job.",
+			},
+			{
+				Name:        "namespace",
+				Type:        proto.ColumnType_STRING,
+				Description: "The namespace of the object the event is about, if namespaced. Filters the events when set, and defaults to the namespace of the connection.",
+				Transform:   transform.FromField("Namespace").NullIfZero(),
+			},
+			{
+				Name:        "filter_keys",
+				Type:        proto.ColumnType_JSON,
+				Description: "The additional keys the event can be filtered on, such as the ID of the job of an allocation.",
+			},
+			{
+				Name:        "filter_key",
+				Type:        proto.ColumnType_STRING,
+				Description: "The key the events are filtered on by Nomad, matching either the key or one of the filter keys of each event. Set to filter the events of the topic, e.g. to the ID of a job to read the events of the job and of its allocations.",
+				Transform:   transform.FromField("FilterKey").NullIfZero(),
+			},
+			{
+				Name:        "index",
+				Type:        proto.ColumnType_INT,
+				Description: "The Raft index of the event. Set a lower bound to replay the events from that index which are still held in the event buffer of the servers.",
+			},
+			{
+				Name:        "payload",
+				Type:        proto.ColumnType_JSON,
+				Description: "The object the event is about, keyed by its type.",
+			},
+			{
+				Name:        "duration",
+				Type:        proto.ColumnType_STRING,
+				Description: "How long the stream is read for, such as 30s or 5m. Capped at 10m.",
+				Transform:   transform.FromField("Duration").NullIfZero(),
+			},
+			{
+				Name:        "max_events",
+				Type:        proto.ColumnType_INT,
+				Description: "The maximum number of events read from the stream. Capped at 10000.",
+			},
+		},
+	}
+}
+
+func listEventStream(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	duration := maxEventStreamDuration
+	if d.EqualsQualString("duration") != "" {
+		var err error
+		duration, err = time.ParseDuration(d.EqualsQualString("duration"))
+		if err != nil || duration <= 0 || duration > maxEventStreamDuration {
+			return nil, fmt.Errorf("duration must be a positive duration of at most %s, e.g. 30s", maxEventStreamDuration)
+		}
+	}
+
+	var maxEvents *int64
+	if d.EqualsQuals["max_events"] != nil {
+		maxEvents = pointerOf(d.EqualsQuals["max_events"].GetInt64Value())
+		if *maxEvents <= 0 || *maxEvents > maxEventStreamEvents {
+			return nil, fmt.Errorf("max_events must be between 1 and %d", maxEventStreamEvents)
+		}
+	}
+
+	topic := api.TopicAll
+	if d.EqualsQualString("topic") != "" {
+		topic = api.Topic(d.EqualsQualString("topic"))
+	}
+	// Nomad matches the filter key against the key and the filter keys of the
+	// events, so it is echoed in its own column rather than matched against key
+	key := "*"
+	if d.EqualsQualString("filter_key") != "" {
+		key = d.EqualsQualString("filter_key")
+	}
+
+	client, err := getClient(ctx, d)
+	if err != nil {
+		logger.Error("nomad_event_stream.listEventStream", "connection_error", err)
+		return nil, err
+	}
+
+	input := queryOptions(d)
+	if d.EqualsQualString("namespace") != "" {
+		input.Namespace = d.EqualsQualString("namespace")
+	}
+
+	// Closing the stream once the duration has elapsed bounds the query
+	streamCtx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	stream, err := client.EventStream().Stream(streamCtx, map[api.Topic][]string{topic: {key}}, eventStreamIndex(d), input)
+	if err != nil {
+		logger.Error("nomad_event_stream.listEventStream", "api_error", err)
+		return nil, err
+	}
+
+	count := int64(0)
+	for events := range stream {
+		if events.Err != nil {
+			// The stream fails once it is closed, either by the server or when the
+			// duration has elapsed
+			if streamCtx.Err() != nil || errors.Is(events.Err, io.EOF) {
+				return nil, nil
+			}
+			logger.Error("nomad_event_stream.listEventStream", "stream_error", events.Err)
+			return nil, events.Err
+		}
+
+		for _, event := range events.Events {
+			d.StreamListItem(ctx, eventStreamEvent{
+				Topic:      string(event.Topic),
+				Type:       event.Type,
+				Key:        event.Key,
+				Namespace:  eventNamespace(event),
+				FilterKeys: event.FilterKeys,
+				FilterKey:  d.EqualsQualString("filter_key"),
+				Index:      event.Index,
+				Payload:    event.Payload,
+				Duration:   d.EqualsQualString("duration"),
+				MaxEvents:  maxEvents,
+			})
+			count++
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if (maxEvents != nil && count >= *maxEvents) || d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// eventStreamIndex returns the index to start streaming events from, set by
+// the quals on the index column.
+func eventStreamIndex(d *plugin.QueryData) uint64 {
+	if d.Quals["index"] == nil {
+		return 0
+	}
+
+	index := uint64(0)
+	for _, q := range d.Quals["index"].Quals {
+		value := uint64(max(q.Value.GetInt64Value(), 0))
+		if q.Operator == ">" {
+			value++
+		}
+		index = max(index, value)
+	}
+	return index
+}
+
+// eventNamespace returns the namespace of the object the event is about. The
+// payload holds the object under the name of its type, and objects which are
+// not namespaced, such as nodes, have no namespace.
+func eventNamespace(event api.Event) string {
+	for _, object := range event.Payload {
+		if fields, ok := object.(map[string]interface{}); ok {
+			if namespace, ok := fields["Namespace"].(string); ok {
+				return namespace
+			}
+		}
+	}
+	return ""
+}
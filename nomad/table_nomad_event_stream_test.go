@@ -0,0 +1,195 @@
+package nomad
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+// testEventStream encodes the events as the newline delimited JSON of the
+// event stream, with a heartbeat between each set of events.
+func testEventStream(t *testing.T, events ...api.Events) string {
+	t.Helper()
+	var b strings.Builder
+	for _, e := range events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Write(data)
+		b.WriteString("\n{}\n")
+	}
+	return b.String()
+}
+
+// sortEventRows orders the rows by index and topic, as the SDK does not keep
+// the order they are streamed in.
+func sortEventRows(rows []testRow) {
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].int("index") != rows[j].int("index") {
+			return rows[i].int("index") < rows[j].int("index")
+		}
+		return rows[i].string("topic") < rows[j].string("topic")
+	})
+}
+
+func testJobEvents() []api.Events {
+	return []api.Events{
+		{Index: 1001, Events: []api.Event{{
+			Topic:      api.TopicJob,
+			Type:       "JobRegistered",
+			Key:        "web",
+			FilterKeys: []string{"web"},
+			Index:      1001,
+			Payload:    map[string]interface{}{"Job": map[string]interface{}{"ID": "web", "Namespace": "apps", "Status": "pending"}},
+		}}},
+		{Index: 1002, Events: []api.Event{
+			{Topic: api.TopicJob, Type: "JobRegistered", Key: "web", Index: 1002, Payload: map[string]interface{}{"Job": map[string]interface{}{"ID": "web", "Namespace": "apps", "Status": "running"}}},
+			{Topic: api.TopicNode, Type: "NodeDrain", Key: "node-1", Index: 1002, Payload: map[string]interface{}{"Node": map[string]interface{}{"ID": "node-1"}}},
+		}},
+	}
+}
+
+func TestListEventStream(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handleRaw("/v1/event/stream", testEventStream(t, testJobEvents()...))
+	server := newTestPluginServer(t, f, "")
+
+	// The stream closed by the server ends the query before the duration
+	rows := testQuery{
+		table:   "nomad_event_stream",
+		columns: []string{"topic", "type", "key", "namespace", "filter_keys", "index", "payload", "duration", "max_events"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"duration": stringQual("1m")}),
+	}.mustExecute(t, server)
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3", len(rows))
+	}
+	sortEventRows(rows)
+
+	first := rows[0]
+	if first.string("topic") != "Job" || first.string("type") != "JobRegistered" || first.string("key") != "web" || first.int("index") != 1001 {
+		t.Errorf("unexpected first event: %v", first)
+	}
+	if first.string("namespace") != "apps" || first.json("filter_keys") != `["web"]` || first.string("duration") != "1m" || !first.isNull("max_events") {
+		t.Errorf("unexpected first event: %v", first)
+	}
+	if !strings.Contains(first.json("payload"), `"Status":"pending"`) {
+		t.Errorf("got payload %s, want the job", first.json("payload"))
+	}
+	if node := rows[2]; node.string("topic") != "Node" || !node.isNull("namespace") {
+		t.Errorf("unexpected node event: %v", node)
+	}
+
+	query := f.requestsTo("/v1/event/stream")[0].URL.Query()
+	if query.Get("topic") != "*:*" || query.Get("index") != "0" {
+		t.Errorf("got topic %q and index %q, want all the events", query.Get("topic"), query.Get("index"))
+	}
+}
+
+func TestListEventStreamFilters(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handleRaw("/v1/event/stream", testEventStream(t, testJobEvents()...))
+	server := newTestPluginServer(t, f, "")
+
+	quals := equalsQuals(map[string]*proto.QualValue{
+		"max_events": int64Qual(2),
+		"topic":      stringQual("Job"),
+		"filter_key": stringQual("web"),
+		"namespace":  stringQual("apps"),
+	})
+	quals["index"] = &proto.Quals{Quals: []*proto.Qual{newQual("index", ">", int64Qual(1000))}}
+
+	rows := testQuery{table: "nomad_event_stream", columns: []string{"index", "topic", "max_events"}, quals: quals}.mustExecute(t, server)
+	sortEventRows(rows)
+	if len(rows) != 2 || rows[1].int("index") != 1002 || rows[1].int("max_events") != 2 {
+		t.Errorf("got rows %v, want the first 2 events", rows)
+	}
+
+	query := f.requestsTo("/v1/event/stream")[0].URL.Query()
+	if query.Get("topic") != "Job:web" || query.Get("namespace") != "apps" {
+		t.Errorf("got topic %q and namespace %q, want the filters of the quals", query.Get("topic"), query.Get("namespace"))
+	}
+	if query.Get("index") != "1001" {
+		t.Errorf("got index %q, want the events after index 1000", query.Get("index"))
+	}
+}
+
+func TestListEventStreamFilterKey(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handleRaw("/v1/event/stream", testEventStream(t, api.Events{Index: 1003, Events: []api.Event{{
+		Topic:      api.TopicAllocation,
+		Type:       "AllocationUpdated",
+		Key:        "alloc-1",
+		FilterKeys: []string{"web", "eval-1"},
+		Index:      1003,
+		Payload:    map[string]interface{}{"Allocation": map[string]interface{}{"ID": "alloc-1", "JobID": "web"}},
+	}}}))
+	server := newTestPluginServer(t, f, "")
+
+	// The event matches the filter key through its filter keys, not its key,
+	// and must not be dropped by the SDK when it checks the quals of the rows
+	rows := testQuery{
+		table:   "nomad_event_stream",
+		columns: []string{"key", "filter_key"},
+		quals: equalsQuals(map[string]*proto.QualValue{
+			"max_events": int64Qual(1),
+			"topic":      stringQual("Allocation"),
+			"filter_key": stringQual("web"),
+		}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].string("key") != "alloc-1" || rows[0].string("filter_key") != "web" {
+		t.Errorf("got rows %v, want the allocation event of the job", rows)
+	}
+
+	if query := f.requestsTo("/v1/event/stream")[0].URL.Query(); query.Get("topic") != "Allocation:web" {
+		t.Errorf("got topic %q, want the allocation events of the web job", query.Get("topic"))
+	}
+}
+
+func TestListEventStreamDuration(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handleStream("/v1/event/stream", testEventStream(t, testJobEvents()[0]))
+	server := newTestPluginServer(t, f, "")
+
+	// The stream stays open, so only the duration ends the query
+	start := time.Now()
+	rows := testQuery{
+		table:   "nomad_event_stream",
+		columns: []string{"index"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"duration": stringQual("200ms")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 {
+		t.Errorf("got %d rows, want the event sent before the duration elapsed", len(rows))
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond || elapsed > 10*time.Second {
+		t.Errorf("query took %s, want it to end once the duration elapsed", elapsed)
+	}
+}
+
+func TestListEventStreamInvalidQuals(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handleRaw("/v1/event/stream", testEventStream(t, testJobEvents()...))
+	server := newTestPluginServer(t, f, "")
+
+	for name, tc := range map[string]struct {
+		quals map[string]*proto.QualValue
+		want  string
+	}{
+		"invalid duration":  {quals: map[string]*proto.QualValue{"duration": stringQual("soon")}, want: "duration must be"},
+		"too long duration": {quals: map[string]*proto.QualValue{"duration": stringQual("1h")}, want: "duration must be"},
+		"no max events":     {quals: map[string]*proto.QualValue{"max_events": int64Qual(0)}, want: "max_events must be"},
+		"too many events":   {quals: map[string]*proto.QualValue{"max_events": int64Qual(100000)}, want: "max_events must be"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := testQuery{table: "nomad_event_stream", columns: []string{"index"}, quals: equalsQuals(tc.quals)}.execute(t, server)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("got error %v, want it to mention %q", err, tc.want)
+			}
+		})
+	}
+}
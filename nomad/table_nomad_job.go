@@ -10,6 +10,16 @@ import (
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
 )
 
+type jobListRow struct {
+	api.JobListStub
+	queryMetaRow
+}
+
+type jobRow struct {
+	api.Job
+	queryMetaRow
+}
+
 func tableNomadJob(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
 		Name:        "nomad_job",
@@ -29,13 +39,24 @@ func tableNomadJob(ctx context.Context) *plugin.Table {
 					Name:    "name",
 					Require: plugin.Optional,
 				},
+				consistencyKeyColumn(),
 			},
 		},
 		Get: &plugin.GetConfig{
-			KeyColumns: plugin.SingleColumn("id"),
+			KeyColumns: append(plugin.SingleColumn("id"), consistencyKeyColumn()),
 			Hydrate:    getJob,
 		},
-		Columns: []*plugin.Column{
+		HydrateConfig: []plugin.HydrateConfig{
+			{
+				Func:    getJobConsulToken,
+				Depends: []plugin.HydrateFunc{getJob},
+			},
+			{
+				Func:    getJobVaultToken,
+				Depends: []plugin.HydrateFunc{getJob},
+			},
+		},
+		Columns: queryMetaColumns([]*plugin.Column{
 			{
 				Name:        "id",
 				Type:        proto.ColumnType_STRING,
@@ -56,6 +77,7 @@ func tableNomadJob(ctx context.Context) *plugin.Table {
 				Name:        "all_at_once",
 				Type:        proto.ColumnType_BOOL,
 				Description: "Whether all tasks should be run in parallel or not.",
+				Hydrate:     getJob,
 			},
 			{
 				Name:        "consul_namespace",
@@ -65,9 +87,10 @@ func tableNomadJob(ctx context.Context) *plugin.Table {
 			},
 			{
 				Name:        "consul_token",
-				Description: "Consul token used by the job.",
+				Description: "Consul token used by the job. Redacted to a fingerprint unless reveal_secrets is set in the connection config.",
 				Type:        proto.ColumnType_STRING,
-				Hydrate:     getJob,
+				Hydrate:     getJobConsulToken,
+				Transform:   transform.FromValue(),
 			},
 			{
 				Name:        "create_index",
@@ -156,9 +179,10 @@ func tableNomadJob(ctx context.Context) *plugin.Table {
 			},
 			{
 				Name:        "vault_token",
-				Description: "Vault token used by the job.",
+				Description: "Vault token used by the job. Redacted to a fingerprint unless reveal_secrets is set in the connection config.",
 				Type:        proto.ColumnType_STRING,
-				Hydrate:     getJob,
+				Hydrate:     getJobVaultToken,
+				Transform:   transform.FromValue(),
 			},
 			{
 				Name:        "version",
@@ -248,7 +272,7 @@ func tableNomadJob(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromField("Name"),
 			},
-		},
+		}),
 	}
 }
 
@@ -259,16 +283,8 @@ func listJobs(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (
 		return nil, err
 	}
 
-	maxLimit := int64(1000)
-	if d.QueryContext.Limit != nil {
-		if *d.QueryContext.Limit < maxLimit {
-			maxLimit = *d.QueryContext.Limit
-		}
-	}
-
-	input := &api.QueryOptions{
-		PerPage: int32(maxLimit),
-	}
+	input := queryOptions(d)
+	input.PerPage = pageSize(d, "namespace", "name")
 
 	if d.EqualsQualString("namespace") != "" {
 		input.Namespace = d.EqualsQualString("namespace")
@@ -281,25 +297,10 @@ func listJobs(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (
 		input.Filter = filter
 	}
 
-	for {
-		jobs, metadata, err := client.Jobs().List(input)
-		if err != nil {
-			plugin.Logger(ctx).Error("nomad_job.listJobs", "api_error", err)
-			return nil, err
-		}
-
-		for _, job := range jobs {
-			d.StreamListItem(ctx, job)
-
-			// Context can be cancelled due to manual cancellation or the limit has been hit
-			if d.RowsRemaining(ctx) == 0 {
-				return nil, nil
-			}
-		}
-		input.NextToken = metadata.NextToken
-		if input.NextToken == "" {
-			break
-		}
+	if err := streamPages(ctx, d, "nomad_job.listJobs", "jobs", input, client.Jobs().List, func(item *api.JobListStub, meta *api.QueryMeta) interface{} {
+		return jobListRow{JobListStub: *item, queryMetaRow: queryMetaRow{meta: meta}}
+	}); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
@@ -309,7 +310,7 @@ func getJob(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (in
 	logger := plugin.Logger(ctx)
 	var id string
 	if h.Item != nil {
-		id = h.Item.(*api.JobListStub).ID
+		id = h.Item.(jobListRow).ID
 	} else {
 		id = d.EqualsQualString("id")
 	}
@@ -326,11 +327,11 @@ func getJob(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (in
 		return nil, err
 	}
 
-	job, _, err := client.Jobs().Info(id, &api.QueryOptions{})
+	job, meta, err := client.Jobs().Info(id, queryOptions(d))
 	if err != nil {
 		logger.Error("nomad_node.getJob", "api_error", err)
 		return nil, err
 	}
 
-	return job, nil
+	return jobRow{Job: *job, queryMetaRow: queryMetaRow{meta: meta}}, nil
 }
@@ -0,0 +1,195 @@
+package nomad
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+type jobScaleStatusInfo struct {
+	JobID          string
+	Namespace      string
+	JobStopped     bool
+	JobCreateIndex uint64
+	JobModifyIndex uint64
+	TaskGroup      string
+	Desired        int
+	Placed         int
+	Running        int
+	Healthy        int
+	Unhealthy      int
+	Events         []api.ScalingEvent
+}
+
+func tableNomadJobScaleStatus(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_job_scale_status",
+		Description: "Retrieve the scale status of the task groups of your jobs.",
+		List: &plugin.ListConfig{
+			Hydrate: listJobScaleStatuses,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "job_id",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "namespace",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "job_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the job.",
+				Transform:   transform.FromField("JobID"),
+			},
+			{
+				Name:        "namespace",
+				Type:        proto.ColumnType_STRING,
+				Description: "The namespace of the job.",
+			},
+			{
+				Name:        "task_group",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the task group.",
+			},
+			{
+				Name:        "job_stopped",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Whether the job is stopped.",
+			},
+			{
+				Name:        "desired",
+				Type:        proto.ColumnType_INT,
+				Description: "The number of allocations the task group is scaled to.",
+			},
+			{
+				Name:        "placed",
+				Type:        proto.ColumnType_INT,
+				Description: "The number of allocations of the task group placed on client nodes.",
+			},
+			{
+				Name:        "running",
+				Type:        proto.ColumnType_INT,
+				Description: "The number of running allocations of the task group.",
+			},
+			{
+				Name:        "healthy",
+				Type:        proto.ColumnType_INT,
+				Description: "The number of healthy allocations of the task group.",
+			},
+			{
+				Name:        "unhealthy",
+				Type:        proto.ColumnType_INT,
+				Description: "The number of unhealthy allocations of the task group.",
+			},
+			{
+				Name:        "events",
+				Type:        proto.ColumnType_JSON,
+				Description: "The recent scaling events of the task group, such as the count changes made by the autoscaler and their errors.",
+			},
+			{
+				Name:        "job_create_index",
+				Type:        proto.ColumnType_INT,
+				Description: "Create index of the job.",
+			},
+			{
+				Name:        "job_modify_index",
+				Type:        proto.ColumnType_INT,
+				Description: "Modify index of the job.",
+			},
+
+			/// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "The title of the task group.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("TaskGroup"),
+			},
+		},
+	}
+}
+
+func listJobScaleStatuses(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	client, err := getClient(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("nomad_job_scale_status.listJobScaleStatuses", "connection_error", err)
+		return nil, err
+	}
+
+	input := queryOptions(d)
+	if d.EqualsQualString("namespace") != "" {
+		input.Namespace = d.EqualsQualString("namespace")
+	}
+
+	// Fetch the scale status directly if the job ID has been provided
+	if d.EqualsQualString("job_id") != "" {
+		if _, err := streamJobScaleStatus(ctx, d, client, d.EqualsQualString("job_id"), input.Namespace); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	streamStatus := func(job *api.JobListStub) (bool, error) {
+		return streamJobScaleStatus(ctx, d, client, job.ID, job.Namespace)
+	}
+	if err := listPages(ctx, d, "nomad_job_scale_status.listJobScaleStatuses", "jobs", input, client.Jobs().List, streamStatus); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// streamJobScaleStatus streams a row for each task group of the job, and
+// returns false once the limit of the query is reached. Jobs deleted since they
+// were listed have no rows.
+func streamJobScaleStatus(ctx context.Context, d *plugin.QueryData, client *api.Client, jobID, namespace string) (bool, error) {
+	input := queryOptions(d)
+	input.Namespace = namespace
+
+	status, _, err := client.Jobs().ScaleStatus(jobID, input)
+	if err != nil {
+		if isNotFoundError(err) {
+			return true, nil
+		}
+		plugin.Logger(ctx).Error("nomad_job_scale_status.streamJobScaleStatus", "api_error", err)
+		return false, err
+	}
+
+	groups := make([]string, 0, len(status.TaskGroups))
+	for group := range status.TaskGroups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	for _, group := range groups {
+		groupStatus := status.TaskGroups[group]
+		d.StreamListItem(ctx, jobScaleStatusInfo{
+			JobID:          status.JobID,
+			Namespace:      status.Namespace,
+			JobStopped:     status.JobStopped,
+			JobCreateIndex: status.JobCreateIndex,
+			JobModifyIndex: status.JobModifyIndex,
+			TaskGroup:      group,
+			Desired:        groupStatus.Desired,
+			Placed:         groupStatus.Placed,
+			Running:        groupStatus.Running,
+			Healthy:        groupStatus.Healthy,
+			Unhealthy:      groupStatus.Unhealthy,
+			Events:         groupStatus.Events,
+		})
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,91 @@
+package nomad
+
+import (
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func testJobScaleStatus() *api.JobScaleStatusResponse {
+	return &api.JobScaleStatusResponse{
+		JobID:     "web",
+		Namespace: "apps",
+		TaskGroups: map[string]api.TaskGroupScaleStatus{
+			"frontend": {
+				Desired: 3,
+				Placed:  3,
+				Running: 2,
+				Healthy: 2,
+				Events:  []api.ScalingEvent{{Count: pointerOf(int64(3)), PreviousCount: 2, Message: "scaling up"}},
+			},
+			"worker": {Desired: 1, Placed: 1, Running: 1, Healthy: 1},
+		},
+	}
+}
+
+func TestListJobScaleStatuses(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/jobs", []*api.JobListStub{{ID: "web", Namespace: "apps"}, {ID: "deleted", Namespace: "apps"}})
+	f.handle("/v1/job/web/scale", testJobScaleStatus())
+	server := newTestPluginServer(t, f, "")
+
+	// Jobs deleted since they were listed are skipped
+	rows := testQuery{
+		table:   "nomad_job_scale_status",
+		columns: []string{"job_id", "namespace", "task_group", "desired", "running", "healthy", "events"},
+	}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want a row per task group", len(rows))
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].string("task_group") < rows[j].string("task_group") })
+
+	frontend := rows[0]
+	if frontend.string("job_id") != "web" || frontend.string("namespace") != "apps" || frontend.string("task_group") != "frontend" {
+		t.Errorf("unexpected frontend row: %v", frontend)
+	}
+	if frontend.int("desired") != 3 || frontend.int("running") != 2 || frontend.int("healthy") != 2 {
+		t.Errorf("unexpected counts of the frontend group: %v", frontend)
+	}
+	if frontend.json("events") == "null" || rows[1].json("events") != "null" {
+		t.Errorf("unexpected events: %s and %s", frontend.json("events"), rows[1].json("events"))
+	}
+
+	if requests := f.requestsTo("/v1/job/web/scale"); len(requests) != 1 || requests[0].URL.Query().Get("namespace") != "apps" {
+		t.Errorf("want the scale status to be read in the namespace of the job")
+	}
+}
+
+func TestListJobScaleStatusesOfJob(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/job/web/scale", testJobScaleStatus())
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_job_scale_status",
+		columns: []string{"job_id", "task_group"},
+		quals: equalsQuals(map[string]*proto.QualValue{
+			"job_id":    stringQual("web"),
+			"namespace": stringQual("apps"),
+		}),
+	}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Errorf("got %d rows, want a row per task group", len(rows))
+	}
+	if len(f.requestsTo("/v1/jobs")) != 0 {
+		t.Errorf("want the jobs not to be listed when the job ID is set")
+	}
+}
+
+func TestListJobScaleStatusesError(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/jobs", []*api.JobListStub{{ID: "web", Namespace: "apps"}})
+	f.handleError("/v1/job/web/scale", http.StatusForbidden, "Permission denied")
+	server := newTestPluginServer(t, f, "")
+
+	if _, err := (testQuery{table: "nomad_job_scale_status", columns: []string{"job_id"}}).execute(t, server); err == nil {
+		t.Errorf("want the permission error to be returned")
+	}
+}
@@ -0,0 +1,116 @@
+package nomad
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func TestListJobs(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handlePages("/v1/jobs",
+		[]*api.JobListStub{{ID: "batch", Name: "batch", Namespace: "default", Type: "batch", Status: "dead"}},
+		[]*api.JobListStub{{ID: "web", Name: "web", Namespace: "default", Type: "service", Status: "running", Priority: 50}},
+	)
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{table: "nomad_job", columns: []string{"id", "name", "type", "status", "priority", "title"}}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 rows across both pages", len(rows))
+	}
+	for _, row := range rows {
+		if row.string("id") == "web" {
+			if row.string("status") != "running" || row.string("type") != "service" || row.int("priority") != 50 || row.string("title") != "web" {
+				t.Errorf("unexpected web job row: %v", row)
+			}
+		}
+	}
+	if requests := f.requestsTo("/v1/jobs"); len(requests) != 2 || requests[1].URL.Query().Get("next_token") != "1" {
+		t.Errorf("expected the second page to be requested with its next token, got %d requests", len(requests))
+	}
+}
+
+func TestListJobsPushesDownQuals(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/jobs", []*api.JobListStub{{ID: "web", Name: "web", Namespace: "apps"}})
+	server := newTestPluginServer(t, f, "")
+
+	testQuery{
+		table:   "nomad_job",
+		columns: []string{"id", "name", "namespace"},
+		quals: equalsQuals(map[string]*proto.QualValue{
+			"namespace": stringQual("apps"),
+			"name":      stringQual("web"),
+		}),
+	}.mustExecute(t, server)
+
+	requests := f.requestsTo("/v1/jobs")
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	query := requests[0].URL.Query()
+	if query.Get("namespace") != "apps" {
+		t.Errorf("got namespace %q, want apps", query.Get("namespace"))
+	}
+	if strings.TrimSpace(query.Get("filter")) != `Name== "web"` {
+		t.Errorf("got filter %q, want the name filter", query.Get("filter"))
+	}
+}
+
+func TestGetJob(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/job/web", &api.Job{
+		ID:          pointerOf("web"),
+		Name:        pointerOf("web"),
+		Namespace:   pointerOf("default"),
+		Datacenters: []string{"dc1"},
+		VaultToken:  pointerOf("s.vault"),
+	})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_job",
+		columns: []string{"id", "name", "datacenters", "vault_token"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"id": stringQual("web")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(rows))
+	}
+	if rows[0].json("datacenters") != `["dc1"]` {
+		t.Errorf("got datacenters %s, want [\"dc1\"]", rows[0].json("datacenters"))
+	}
+	if !strings.HasPrefix(rows[0].string("vault_token"), redactedSecretPrefix) {
+		t.Errorf("got vault token %q, want it redacted", rows[0].string("vault_token"))
+	}
+	if len(f.requestsTo("/v1/jobs")) != 0 {
+		t.Errorf("expected the job to be fetched without listing jobs")
+	}
+}
+
+func TestGetJobNotFound(t *testing.T) {
+	f := newFakeNomad(t)
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_job",
+		columns: []string{"id", "name"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"id": stringQual("missing")}),
+	}.mustExecute(t, server)
+	if len(rows) != 0 {
+		t.Errorf("got %d rows, want none for a missing job", len(rows))
+	}
+}
+
+func TestListJobsError(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handleError("/v1/jobs", http.StatusInternalServerError, "rpc error: leader lost")
+	server := newTestPluginServer(t, f, "")
+
+	_, err := testQuery{table: "nomad_job", columns: []string{"id"}}.execute(t, server)
+	if err == nil || !strings.Contains(err.Error(), "leader lost") {
+		t.Errorf("got error %v, want the API error", err)
+	}
+}
@@ -0,0 +1,186 @@
+package nomad
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+type licenseInfo struct {
+	LicenseID       string
+	CustomerID      string
+	InstallationID  string
+	Product         string
+	IssueTime       time.Time
+	StartTime       time.Time
+	ExpirationTime  time.Time
+	TerminationTime time.Time
+	DaysUntilExpiry *int64
+	Features        []string
+	Modules         []string
+	MaxNodes        *int64
+	Flags           map[string]interface{}
+	ConfigOutdated  bool
+}
+
+func tableNomadLicense(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_license",
+		Description: "Retrieve information about the license of your cluster. Requires Nomad Enterprise.",
+		List: &plugin.ListConfig{
+			Hydrate:      listLicense,
+			IgnoreConfig: enterpriseOnlyIgnoreConfig(),
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "license_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the license.",
+				Transform:   transform.FromField("LicenseID"),
+			},
+			{
+				Name:        "customer_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the customer the license was issued to.",
+				Transform:   transform.FromField("CustomerID"),
+			},
+			{
+				Name:        "installation_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the installation the license is locked to, if any.",
+				Transform:   transform.FromField("InstallationID").NullIfZero(),
+			},
+			{
+				Name:        "product",
+				Type:        proto.ColumnType_STRING,
+				Description: "The product the license is valid for.",
+			},
+			{
+				Name:        "issue_time",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The time the license was issued.",
+				Transform:   transform.FromField("IssueTime").NullIfZero(),
+			},
+			{
+				Name:        "start_time",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The time the license starts being valid.",
+				Transform:   transform.FromField("StartTime").NullIfZero(),
+			},
+			{
+				Name:        "expiration_time",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The time the license expires.",
+				Transform:   transform.FromField("ExpirationTime").NullIfZero(),
+			},
+			{
+				Name:        "termination_time",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The time the license stops working altogether, after it expired.",
+				Transform:   transform.FromField("TerminationTime").NullIfZero(),
+			},
+			{
+				Name:        "days_until_expiry",
+				Type:        proto.ColumnType_INT,
+				Description: "The number of whole days left until the license expires, negative once it has expired.",
+			},
+			{
+				Name:        "features",
+				Type:        proto.ColumnType_JSON,
+				Description: "The features enabled by the license.",
+			},
+			{
+				Name:        "modules",
+				Type:        proto.ColumnType_JSON,
+				Description: "The enterprise modules enabled by the license.",
+			},
+			{
+				Name:        "max_nodes",
+				Type:        proto.ColumnType_INT,
+				Description: "The maximum number of client nodes allowed by the license, if limited.",
+			},
+			{
+				Name:        "flags",
+				Type:        proto.ColumnType_JSON,
+				Description: "The flags specific to the license.",
+			},
+			{
+				Name:        "config_outdated",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Whether the license of the server configuration is older than the license in use.",
+			},
+
+			/// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "The title of the license.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("LicenseID"),
+			},
+		},
+	}
+}
+
+func listLicense(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	client, err := getClient(ctx, d)
+	if err != nil {
+		logger.Error("nomad_license.listLicense", "connection_error", err)
+		return nil, err
+	}
+
+	reply, _, err := client.Operator().LicenseGet(queryOptions(d))
+	if err != nil {
+		logger.Error("nomad_license.listLicense", "api_error", err)
+		return nil, err
+	}
+	if reply.License == nil {
+		return nil, nil
+	}
+
+	license := reply.License
+	row := licenseInfo{
+		LicenseID:       license.LicenseID,
+		CustomerID:      license.CustomerID,
+		InstallationID:  license.InstallationID,
+		Product:         license.Product,
+		IssueTime:       license.IssueTime,
+		StartTime:       license.StartTime,
+		ExpirationTime:  license.ExpirationTime,
+		TerminationTime: license.TerminationTime,
+		Features:        license.Features,
+		Modules:         license.Modules,
+		MaxNodes:        licenseMaxNodes(license.Flags),
+		Flags:           license.Flags,
+		ConfigOutdated:  reply.ConfigOutdated,
+	}
+	if !license.ExpirationTime.IsZero() {
+		row.DaysUntilExpiry = pointerOf(daysUntil(license.ExpirationTime, time.Now()))
+	}
+	d.StreamListItem(ctx, row)
+
+	return nil, nil
+}
+
+// daysUntil returns the number of whole days from now until t, rounded down so
+// that it turns negative as soon as t has passed.
+func daysUntil(t time.Time, now time.Time) int64 {
+	return int64(math.Floor(t.Sub(now).Hours() / 24))
+}
+
+// licenseMaxNodes returns the maximum number of client nodes set in the flags
+// of the license, which only limited licenses have.
+func licenseMaxNodes(flags map[string]interface{}) *int64 {
+	switch maxNodes := flags["max_nodes"].(type) {
+	case float64:
+		return pointerOf(int64(maxNodes))
+	case int:
+		return pointerOf(int64(maxNodes))
+	}
+	return nil
+}
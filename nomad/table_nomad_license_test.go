@@ -0,0 +1,80 @@
+package nomad
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+func TestListLicense(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/operator/license", &api.LicenseReply{
+		License: &api.License{
+			LicenseID:      "license-1",
+			CustomerID:     "customer-1",
+			Product:        "nomad",
+			IssueTime:      time.Now().Add(-24 * time.Hour),
+			ExpirationTime: time.Now().Add(30*24*time.Hour + time.Hour),
+			Features:       []string{"Sentinel Policies", "Resource Quotas"},
+			Modules:        []string{"governance-policy"},
+			Flags:          map[string]interface{}{"max_nodes": 50},
+		},
+	})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_license",
+		columns: []string{"license_id", "customer_id", "days_until_expiry", "features", "max_nodes", "termination_time"},
+	}.mustExecute(t, server)
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want a single row", len(rows))
+	}
+
+	license := rows[0]
+	if license.string("license_id") != "license-1" || license.string("customer_id") != "customer-1" {
+		t.Errorf("unexpected license: %v", license)
+	}
+	if license.int("days_until_expiry") != 30 || license.int("max_nodes") != 50 {
+		t.Errorf("got %d days until expiry and %d max nodes, want 30 and 50", license.int("days_until_expiry"), license.int("max_nodes"))
+	}
+	if license.json("features") != `["Sentinel Policies","Resource Quotas"]` || !license.isNull("termination_time") {
+		t.Errorf("unexpected license: %v", license)
+	}
+}
+
+func TestListLicenseWithoutEnterprise(t *testing.T) {
+	// Nomad without Enterprise responds with no content, while older versions
+	// have no license endpoint at all
+	for _, status := range []int{http.StatusNoContent, http.StatusNotFound} {
+		f := newFakeNomad(t)
+		if status != http.StatusNotFound {
+			f.handleError("/v1/operator/license", status, "")
+		}
+		server := newTestPluginServer(t, f, "")
+
+		rows := testQuery{table: "nomad_license", columns: []string{"license_id"}}.mustExecute(t, server)
+		if len(rows) != 0 {
+			t.Errorf("got %d rows for status %d, want none on clusters without a license", len(rows), status)
+		}
+	}
+}
+
+func TestDaysUntil(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		t    time.Time
+		want int64
+	}{
+		{now.Add(48 * time.Hour), 2},
+		{now.Add(47 * time.Hour), 1},
+		{now.Add(time.Hour), 0},
+		{now.Add(-time.Hour), -1},
+		{now.Add(-48 * time.Hour), -2},
+	} {
+		if got := daysUntil(tc.t, now); got != tc.want {
+			t.Errorf("daysUntil(%s) = %d, want %d", tc.t.Sub(now), got, tc.want)
+		}
+	}
+}
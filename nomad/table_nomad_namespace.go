@@ -9,6 +9,11 @@ import (
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
 )
 
+type namespaceRow struct {
+	api.Namespace
+	queryMetaRow
+}
+
 func tableNomadNamespace(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
 		Name:        "nomad_namespace",
@@ -20,13 +25,14 @@ func tableNomadNamespace(ctx context.Context) *plugin.Table {
 					Name:    "create_index",
 					Require: plugin.Optional,
 				},
+				consistencyKeyColumn(),
 			},
 		},
 		Get: &plugin.GetConfig{
-			KeyColumns: plugin.SingleColumn("name"),
+			KeyColumns: append(plugin.SingleColumn("name"), consistencyKeyColumn()),
 			Hydrate:    getNamespace,
 		},
-		Columns: []*plugin.Column{
+		Columns: queryMetaColumns([]*plugin.Column{
 			{
 				Name:        "name",
 				Type:        proto.ColumnType_STRING,
@@ -70,7 +76,7 @@ func tableNomadNamespace(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromField("Name"),
 			},
-		},
+		}),
 	}
 }
 
@@ -81,39 +87,16 @@ func listNamespaces(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateD
 		return nil, err
 	}
 
-	maxLimit := int64(1000)
-	if d.QueryContext.Limit != nil {
-		if *d.QueryContext.Limit < maxLimit {
-			maxLimit = *d.QueryContext.Limit
-		}
-	}
-
-	input := &api.QueryOptions{
-		PerPage: int32(maxLimit),
-	}
+	input := queryOptions(d)
+	input.PerPage = pageSize(d)
 	if d.EqualsQuals["create_index"] != nil {
 		input.Prefix = d.EqualsQuals["create_index"].GetStringValue()
 	}
 
-	for {
-		namespaces, metadata, err := client.Namespaces().List(input)
-		if err != nil {
-			plugin.Logger(ctx).Error("nomad_namespace.listNamespaces", "api_error", err)
-			return nil, err
-		}
-
-		for _, namespace := range namespaces {
-			d.StreamListItem(ctx, namespace)
-
-			// Context can be cancelled due to manual cancellation or the limit has been hit
-			if d.RowsRemaining(ctx) == 0 {
-				return nil, nil
-			}
-		}
-		input.NextToken = metadata.NextToken
-		if input.NextToken == "" {
-			break
-		}
+	if err := streamPages(ctx, d, "nomad_namespace.listNamespaces", "namespaces", input, client.Namespaces().List, func(item *api.Namespace, meta *api.QueryMeta) interface{} {
+		return namespaceRow{Namespace: *item, queryMetaRow: queryMetaRow{meta: meta}}
+	}); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
@@ -135,11 +118,11 @@ func getNamespace(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDat
 		return nil, err
 	}
 
-	namespace, _, err := client.Namespaces().Info(name, &api.QueryOptions{})
+	namespace, meta, err := client.Namespaces().Info(name, queryOptions(d))
 	if err != nil {
 		logger.Error("nomad_node.getNamespace", "api_error", err)
 		return nil, err
 	}
 
-	return namespace, nil
+	return namespaceRow{Namespace: *namespace, queryMetaRow: queryMetaRow{meta: meta}}, nil
 }
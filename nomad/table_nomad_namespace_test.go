@@ -0,0 +1,45 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func TestListNamespaces(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handlePages("/v1/namespaces",
+		[]*api.Namespace{{Name: "default", Description: "Default shared namespace"}},
+		[]*api.Namespace{{Name: "apps", Meta: map[string]string{"team": "web"}}},
+	)
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{table: "nomad_namespace", columns: []string{"name", "description", "meta"}}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 rows across both pages", len(rows))
+	}
+	for _, row := range rows {
+		if row.string("name") == "apps" && row.json("meta") != `{"team":"web"}` {
+			t.Errorf("unexpected apps namespace row: %v", row)
+		}
+	}
+}
+
+func TestGetNamespace(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/namespace/apps", &api.Namespace{Name: "apps", Description: "Applications", Quota: "small"})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_namespace",
+		columns: []string{"name", "description", "quota"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"name": stringQual("apps")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].string("description") != "Applications" || rows[0].string("quota") != "small" {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+	if len(f.requestsTo("/v1/namespaces")) != 0 {
+		t.Errorf("expected the namespace to be fetched without listing namespaces")
+	}
+}
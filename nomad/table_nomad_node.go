@@ -10,6 +10,16 @@ import (
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
 )
 
+type nodeListRow struct {
+	api.NodeListStub
+	queryMetaRow
+}
+
+type nodeRow struct {
+	api.Node
+	queryMetaRow
+}
+
 func tableNomadNode(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
 		Name:        "nomad_node",
@@ -25,13 +35,14 @@ func tableNomadNode(ctx context.Context) *plugin.Table {
 					Name:    "create_index",
 					Require: plugin.Optional,
 				},
+				consistencyKeyColumn(),
 			},
 		},
 		Get: &plugin.GetConfig{
-			KeyColumns: plugin.SingleColumn("id"),
+			KeyColumns: append(plugin.SingleColumn("id"), consistencyKeyColumn()),
 			Hydrate:    getNode,
 		},
-		Columns: []*plugin.Column{
+		Columns: queryMetaColumns([]*plugin.Column{
 			{
 				Name:        "id",
 				Type:        proto.ColumnType_STRING,
@@ -133,12 +144,14 @@ func tableNomadNode(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_JSON,
 				Description: "A map containing information about the CSI controller plugins installed on the node.",
 				Hydrate:     getNode,
+				Transform:   transform.FromField("CSIControllerPlugins"),
 			},
 			{
 				Name:        "csi_node_plugins",
 				Type:        proto.ColumnType_JSON,
 				Description: "A map containing information about the CSI node plugins installed on the node.",
 				Hydrate:     getNode,
+				Transform:   transform.FromField("CSINodePlugins"),
 			},
 			{
 				Name:        "attributes",
@@ -203,7 +216,7 @@ func tableNomadNode(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromField("Name"),
 			},
-		},
+		}),
 	}
 }
 
@@ -214,16 +227,8 @@ func listNodes(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData)
 		return nil, err
 	}
 
-	maxLimit := int64(1000)
-	if d.QueryContext.Limit != nil {
-		if *d.QueryContext.Limit < maxLimit {
-			maxLimit = *d.QueryContext.Limit
-		}
-	}
-
-	input := &api.QueryOptions{
-		PerPage: int32(maxLimit),
-	}
+	input := queryOptions(d)
+	input.PerPage = pageSize(d, "name")
 	if d.EqualsQuals["create_index"] != nil {
 		input.Prefix = d.EqualsQuals["create_index"].GetStringValue()
 	}
@@ -232,25 +237,10 @@ func listNodes(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData)
 		input.Filter = filter
 	}
 
-	for {
-		nodes, metadata, err := client.Nodes().List(input)
-		if err != nil {
-			plugin.Logger(ctx).Error("nomad_node.listNodes", "api_error", err)
-			return nil, err
-		}
-
-		for _, node := range nodes {
-			d.StreamListItem(ctx, node)
-
-			// Context can be cancelled due to manual cancellation or the limit has been hit
-			if d.RowsRemaining(ctx) == 0 {
-				return nil, nil
-			}
-		}
-		input.NextToken = metadata.NextToken
-		if input.NextToken == "" {
-			break
-		}
+	if err := streamPages(ctx, d, "nomad_node.listNodes", "nodes", input, client.Nodes().List, func(item *api.NodeListStub, meta *api.QueryMeta) interface{} {
+		return nodeListRow{NodeListStub: *item, queryMetaRow: queryMetaRow{meta: meta}}
+	}); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
@@ -260,7 +250,7 @@ func getNode(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (i
 	logger := plugin.Logger(ctx)
 	var id string
 	if h.Item != nil {
-		id = h.Item.(*api.NodeListStub).ID
+		id = h.Item.(nodeListRow).ID
 	} else {
 		id = d.EqualsQualString("id")
 	}
@@ -272,11 +262,11 @@ func getNode(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (i
 		return nil, err
 	}
 
-	node, _, err := client.Nodes().Info(id, &api.QueryOptions{})
+	node, meta, err := client.Nodes().Info(id, queryOptions(d))
 	if err != nil {
 		logger.Error("nomad_node.getNode", "api_error", err)
 		return nil, err
 	}
 
-	return node, nil
+	return nodeRow{Node: *node, queryMetaRow: queryMetaRow{meta: meta}}, nil
 }
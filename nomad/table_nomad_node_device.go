@@ -0,0 +1,189 @@
+package nomad
+
+import (
+	"context"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+type nodeDeviceInfo struct {
+	NodeID            string
+	NodeName          string
+	Datacenter        string
+	Vendor            string
+	Type              string
+	Name              string
+	InstanceID        string
+	Healthy           bool
+	HealthDescription string
+	PciBusID          string
+	Attributes        map[string]string
+}
+
+func tableNomadNodeDevice(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_node_device",
+		Description: "Retrieve information about the device instances fingerprinted on your nodes.",
+		List: &plugin.ListConfig{
+			Hydrate: listNodeDevices,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "node_id",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "node_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the node the device is attached to.",
+				Transform:   transform.FromField("NodeID"),
+			},
+			{
+				Name:        "node_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the node the device is attached to.",
+			},
+			{
+				Name:        "datacenter",
+				Type:        proto.ColumnType_STRING,
+				Description: "The datacenter in which the node is located.",
+			},
+			{
+				Name:        "vendor",
+				Type:        proto.ColumnType_STRING,
+				Description: "The vendor of the device, e.g. nvidia.",
+			},
+			{
+				Name:        "type",
+				Type:        proto.ColumnType_STRING,
+				Description: "The type of the device, e.g. gpu.",
+			},
+			{
+				Name:        "name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The specific model of the device.",
+			},
+			{
+				Name:        "instance_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the device instance.",
+				Transform:   transform.FromField("InstanceID"),
+			},
+			{
+				Name:        "healthy",
+				Type:        proto.ColumnType_BOOL,
+				Description: "True if the device instance is healthy.",
+			},
+			{
+				Name:        "health_description",
+				Type:        proto.ColumnType_STRING,
+				Description: "A human readable description of why the device instance may be unhealthy.",
+			},
+			{
+				Name:        "pci_bus_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The PCI bus ID of the device instance.",
+				Transform:   transform.FromField("PciBusID"),
+			},
+			{
+				Name:        "attributes",
+				Type:        proto.ColumnType_JSON,
+				Description: "A map of the attributes reported by the device plugin, with units where applicable.",
+			},
+
+			/// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "The title of the node device.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("InstanceID"),
+			},
+		},
+	}
+}
+
+func listNodeDevices(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	client, err := getClient(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("nomad_node_device.listNodeDevices", "connection_error", err)
+		return nil, err
+	}
+
+	// Restrict to a single node if the ID has been provided
+	if d.EqualsQualString("node_id") != "" {
+		node, _, err := client.Nodes().Info(d.EqualsQualString("node_id"), queryOptions(d))
+		if err != nil {
+			plugin.Logger(ctx).Error("nomad_node_device.listNodeDevices", "api_error", err)
+			return nil, err
+		}
+		streamNodeDevices(ctx, d, node.ID, node.Name, node.Datacenter, node.NodeResources)
+		return nil, nil
+	}
+
+	// The node resources are only returned in the list response when requested
+	input := queryOptions(d)
+	input.Params = map[string]string{"resources": "true"}
+
+	err = listPages(ctx, d, "nomad_node_device.listNodeDevices", "nodes", input, client.Nodes().List, func(node *api.NodeListStub) (bool, error) {
+		return streamNodeDevices(ctx, d, node.ID, node.Name, node.Datacenter, node.NodeResources), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// streamNodeDevices streams one row per device instance of the node and
+// returns false once no further rows are required.
+func streamNodeDevices(ctx context.Context, d *plugin.QueryData, nodeID, nodeName, datacenter string, resources *api.NodeResources) bool {
+	if resources == nil {
+		return true
+	}
+
+	for _, device := range resources.Devices {
+		if device == nil {
+			continue
+		}
+		attributes := map[string]string{}
+		for key, attribute := range device.Attributes {
+			if attribute != nil {
+				attributes[key] = attribute.String()
+			}
+		}
+
+		for _, instance := range device.Instances {
+			if instance == nil {
+				continue
+			}
+			row := nodeDeviceInfo{
+				NodeID:            nodeID,
+				NodeName:          nodeName,
+				Datacenter:        datacenter,
+				Vendor:            device.Vendor,
+				Type:              device.Type,
+				Name:              device.Name,
+				InstanceID:        instance.ID,
+				Healthy:           instance.Healthy,
+				HealthDescription: instance.HealthDescription,
+				Attributes:        attributes,
+			}
+			if instance.Locality != nil {
+				row.PciBusID = instance.Locality.PciBusID
+			}
+			d.StreamListItem(ctx, row)
+
+			// Context can be cancelled due to manual cancellation or the limit has been hit
+			if d.RowsRemaining(ctx) == 0 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
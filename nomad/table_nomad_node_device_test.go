@@ -0,0 +1,69 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func testNodeResources() *api.NodeResources {
+	return &api.NodeResources{
+		Devices: []*api.NodeDeviceResource{
+			{
+				Vendor: "nvidia",
+				Type:   "gpu",
+				Name:   "Tesla T4",
+				Instances: []*api.NodeDevice{
+					{ID: "GPU-1", Healthy: true, Locality: &api.NodeDeviceLocality{PciBusID: "00000000:00:1E.0"}},
+					{ID: "GPU-2", Healthy: false, HealthDescription: "XID error"},
+				},
+			},
+		},
+	}
+}
+
+func TestListNodeDevices(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handlePages("/v1/nodes",
+		[]*api.NodeListStub{{ID: "node-1", Name: "gpu-1", Datacenter: "dc1", NodeResources: testNodeResources()}},
+		[]*api.NodeListStub{{ID: "node-2", Name: "cpu-1", Datacenter: "dc1"}},
+	)
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_node_device",
+		columns: []string{"node_id", "node_name", "vendor", "type", "name", "instance_id", "healthy", "pci_bus_id"},
+	}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want one per device instance", len(rows))
+	}
+	for _, row := range rows {
+		if row.string("instance_id") == "GPU-1" && (!row.bool("healthy") || row.string("pci_bus_id") != "00000000:00:1E.0" || row.string("node_name") != "gpu-1") {
+			t.Errorf("unexpected GPU-1 row: %v", row)
+		}
+	}
+
+	requests := f.requestsTo("/v1/nodes")
+	if len(requests) != 2 || requests[0].URL.Query().Get("resources") != "true" {
+		t.Errorf("expected the node resources to be requested on every page")
+	}
+}
+
+func TestListNodeDevicesByNode(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/node/node-1", &api.Node{ID: "node-1", Name: "gpu-1", NodeResources: testNodeResources()})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_node_device",
+		columns: []string{"node_id", "instance_id"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"node_id": stringQual("node-1")}),
+	}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Errorf("got %d rows, want 2", len(rows))
+	}
+	if len(f.requestsTo("/v1/nodes")) != 0 {
+		t.Errorf("expected the node to be fetched without listing nodes")
+	}
+}
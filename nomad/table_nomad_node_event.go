@@ -0,0 +1,152 @@
+package nomad
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+type nodeEventInfo struct {
+	NodeID      string
+	NodeName    string
+	Message     string
+	Subsystem   string
+	Details     map[string]string
+	Timestamp   time.Time
+	CreateIndex uint64
+}
+
+func tableNomadNodeEvent(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_node_event",
+		Description: "Retrieve information about the lifecycle events of your nodes.",
+		List: &plugin.ListConfig{
+			Hydrate: listNodeEvents,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "node_id",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "node_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the node the event belongs to.",
+				Transform:   transform.FromField("NodeID"),
+			},
+			{
+				Name:        "node_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the node the event belongs to.",
+			},
+			{
+				Name:        "message",
+				Type:        proto.ColumnType_STRING,
+				Description: "The message describing the node event.",
+			},
+			{
+				Name:        "subsystem",
+				Type:        proto.ColumnType_STRING,
+				Description: "The subsystem that emitted the event, e.g. Cluster, Drain or Driver.",
+			},
+			{
+				Name:        "timestamp",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Description: "The time at which the event occurred.",
+			},
+			{
+				Name:        "create_index",
+				Type:        proto.ColumnType_INT,
+				Description: "The index at which the event was created.",
+			},
+			{
+				Name:        "details",
+				Type:        proto.ColumnType_JSON,
+				Description: "A map of additional details about the event.",
+			},
+
+			/// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "The title of the node event.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Message"),
+			},
+		},
+	}
+}
+
+func listNodeEvents(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	client, err := getClient(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("nomad_node_event.listNodeEvents", "connection_error", err)
+		return nil, err
+	}
+
+	// Restrict to a single node if the ID has been provided
+	if d.EqualsQualString("node_id") != "" {
+		node, _, err := client.Nodes().Info(d.EqualsQualString("node_id"), queryOptions(d))
+		if err != nil {
+			plugin.Logger(ctx).Error("nomad_node_event.listNodeEvents", "api_error", err)
+			return nil, err
+		}
+		streamNodeEvents(ctx, d, node)
+		return nil, nil
+	}
+
+	// Events are not part of the node list stub, so each node is fetched.
+	// Nodes garbage collected since they were listed are skipped.
+	streamEvents := func(stub *api.NodeListStub) (bool, error) {
+		node, _, err := client.Nodes().Info(stub.ID, queryOptions(d))
+		if err != nil {
+			if isNotFoundError(err) {
+				return true, nil
+			}
+			plugin.Logger(ctx).Error("nomad_node_event.listNodeEvents", "api_error", err)
+			return false, err
+		}
+		return streamNodeEvents(ctx, d, node), nil
+	}
+
+	if err := listPages(ctx, d, "nomad_node_event.listNodeEvents", "nodes", queryOptions(d), client.Nodes().List, streamEvents); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// streamNodeEvents streams one row per event of the node and returns false
+// once no further rows are required.
+func streamNodeEvents(ctx context.Context, d *plugin.QueryData, node *api.Node) bool {
+	if node == nil {
+		return true
+	}
+
+	for _, event := range node.Events {
+		if event == nil {
+			continue
+		}
+		d.StreamListItem(ctx, nodeEventInfo{
+			NodeID:      node.ID,
+			NodeName:    node.Name,
+			Message:     event.Message,
+			Subsystem:   event.Subsystem,
+			Details:     event.Details,
+			Timestamp:   event.Timestamp,
+			CreateIndex: event.CreateIndex,
+		})
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
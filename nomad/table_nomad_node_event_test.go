@@ -0,0 +1,66 @@
+package nomad
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+func TestListNodeEvents(t *testing.T) {
+	registered := time.Date(2023, 4, 20, 10, 0, 0, 0, time.UTC)
+	f := newFakeNomad(t)
+	f.handle("/v1/nodes", []*api.NodeListStub{{ID: "node-1"}, {ID: "node-2"}})
+	f.handle("/v1/node/node-1", &api.Node{ID: "node-1", Name: "client-1", Events: []*api.NodeEvent{
+		{Message: "Node registered", Subsystem: "Cluster", Timestamp: registered, CreateIndex: 10},
+	}})
+	f.handle("/v1/node/node-2", &api.Node{ID: "node-2", Name: "client-2", Events: []*api.NodeEvent{
+		{Message: "Node heartbeat missed", Subsystem: "Cluster", Details: map[string]string{"reason": "timeout"}},
+	}})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_node_event",
+		columns: []string{"node_id", "node_name", "message", "subsystem", "timestamp", "details"},
+	}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want one per event", len(rows))
+	}
+	for _, row := range rows {
+		switch row.string("node_id") {
+		case "node-1":
+			if row.string("message") != "Node registered" || row["timestamp"].GetTimestampValue().AsTime() != registered {
+				t.Errorf("unexpected node-1 row: %v", row)
+			}
+		case "node-2":
+			if row.json("details") != `{"reason":"timeout"}` {
+				t.Errorf("unexpected node-2 row: %v", row)
+			}
+		}
+	}
+}
+
+func TestListNodeEventsNodeGone(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/nodes", []*api.NodeListStub{{ID: "node-gone"}, {ID: "node-1"}})
+	f.handleError("/v1/node/node-gone", http.StatusNotFound, "node not found")
+	f.handle("/v1/node/node-1", &api.Node{ID: "node-1", Name: "client-1", Events: []*api.NodeEvent{{Message: "Node registered"}}})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{table: "nomad_node_event", columns: []string{"node_id", "message"}}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].string("node_id") != "node-1" {
+		t.Errorf("got rows %v, want the events of node-1 only", rows)
+	}
+}
+
+func TestListNodeEventsError(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/nodes", []*api.NodeListStub{{ID: "node-1"}})
+	f.handleError("/v1/node/node-1", http.StatusInternalServerError, "node lookup failed")
+	server := newTestPluginServer(t, f, "")
+
+	if _, err := (testQuery{table: "nomad_node_event", columns: []string{"message"}}).execute(t, server); err == nil {
+		t.Errorf("expected the error fetching the node to be returned")
+	}
+}
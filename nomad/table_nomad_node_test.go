@@ -0,0 +1,90 @@
+package nomad
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func TestListNodes(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handlePages("/v1/nodes",
+		[]*api.NodeListStub{{ID: "node-1", Name: "client-1", Datacenter: "dc1", Status: "ready", Drain: false}},
+		[]*api.NodeListStub{{ID: "node-2", Name: "client-2", Datacenter: "dc2", Status: "down", Drain: true}},
+	)
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{table: "nomad_node", columns: []string{"id", "name", "datacenter", "status", "drain"}}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 rows across both pages", len(rows))
+	}
+	for _, row := range rows {
+		if row.string("id") == "node-2" && (row.string("datacenter") != "dc2" || row.string("status") != "down" || !row.bool("drain")) {
+			t.Errorf("unexpected node-2 row: %v", row)
+		}
+	}
+}
+
+func TestListNodesPushesDownQuals(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/nodes", []*api.NodeListStub{{ID: "node-1", Name: "client-1"}})
+	server := newTestPluginServer(t, f, "")
+
+	testQuery{
+		table:   "nomad_node",
+		columns: []string{"id", "name"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"name": stringQual("client-1")}),
+		limit:   5,
+	}.mustExecute(t, server)
+
+	requests := f.requestsTo("/v1/nodes")
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	if filter := strings.TrimSpace(requests[0].URL.Query().Get("filter")); filter != `Name== "client-1"` {
+		t.Errorf("got filter %q, want the name filter", filter)
+	}
+	if perPage := requests[0].URL.Query().Get("per_page"); perPage != "5" {
+		t.Errorf("got per_page %q, want the query limit", perPage)
+	}
+}
+
+func TestGetNode(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/node/node-1", &api.Node{
+		ID:         "node-1",
+		Name:       "client-1",
+		Datacenter: "dc1",
+		HTTPAddr:   "10.0.0.2:4646",
+		Attributes: map[string]string{"kernel.name": "linux"},
+	})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_node",
+		columns: []string{"id", "name", "http_address", "attributes"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"id": stringQual("node-1")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(rows))
+	}
+	if rows[0].string("http_address") != "10.0.0.2:4646" || rows[0].json("attributes") != `{"kernel.name":"linux"}` {
+		t.Errorf("unexpected node row: %v", rows[0])
+	}
+}
+
+func TestGetNodeNotFound(t *testing.T) {
+	f := newFakeNomad(t)
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_node",
+		columns: []string{"id", "name"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"id": stringQual("missing")}),
+	}.mustExecute(t, server)
+	if len(rows) != 0 {
+		t.Errorf("got %d rows, want none for a missing node", len(rows))
+	}
+}
@@ -9,22 +9,36 @@ import (
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
 )
 
+type csiPluginListRow struct {
+	api.CSIPluginListStub
+	queryMetaRow
+}
+
+type csiPluginRow struct {
+	api.CSIPlugin
+	queryMetaRow
+}
+
 func tableNomadPlugin(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
 		Name:        "nomad_plugin",
 		Description: "Retrieve information about your plugins.",
 		List: &plugin.ListConfig{
 			Hydrate: listPlugins,
+			KeyColumns: []*plugin.KeyColumn{
+				consistencyKeyColumn(),
+			},
 		},
 		Get: &plugin.GetConfig{
-			KeyColumns: plugin.SingleColumn("id"),
+			KeyColumns: append(plugin.SingleColumn("id"), consistencyKeyColumn()),
 			Hydrate:    getPlugin,
 		},
-		Columns: []*plugin.Column{
+		Columns: queryMetaColumns([]*plugin.Column{
 			{
 				Name:        "id",
 				Type:        proto.ColumnType_STRING,
 				Description: "A unique identifier for the CSI plugin.",
+				Transform:   transform.FromField("ID"),
 			},
 			{
 				Name:        "provider",
@@ -98,7 +112,7 @@ func tableNomadPlugin(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromField("ID"),
 			},
-		},
+		}),
 	}
 }
 
@@ -109,36 +123,15 @@ func listPlugins(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData
 		return nil, err
 	}
 
-	maxLimit := int64(1000)
-	if d.QueryContext.Limit != nil {
-		if *d.QueryContext.Limit < maxLimit {
-			maxLimit = *d.QueryContext.Limit
-		}
-	}
-
-	input := &api.QueryOptions{
-		PerPage: int32(maxLimit),
-	}
+	// CSI plugins are not namespaced, unlike the volumes they provide, so the
+	// namespace of the connection does not apply
+	input := queryOptions(d)
+	input.PerPage = pageSize(d)
 
-	for {
-		plugins, metadata, err := client.CSIPlugins().List(input)
-		if err != nil {
-			plugin.Logger(ctx).Error("nomad_plugin.listPlugins", "api_error", err)
-			return nil, err
-		}
-
-		for _, plugin := range plugins {
-			d.StreamListItem(ctx, plugin)
-
-			// Context can be cancelled due to manual cancellation or the limit has been hit
-			if d.RowsRemaining(ctx) == 0 {
-				return nil, nil
-			}
-		}
-		input.NextToken = metadata.NextToken
-		if input.NextToken == "" {
-			break
-		}
+	if err := streamPages(ctx, d, "nomad_plugin.listPlugins", "CSI plugins", input, client.CSIPlugins().List, func(item *api.CSIPluginListStub, meta *api.QueryMeta) interface{} {
+		return csiPluginListRow{CSIPluginListStub: *item, queryMetaRow: queryMetaRow{meta: meta}}
+	}); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
@@ -148,7 +141,7 @@ func getPlugin(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData)
 	logger := plugin.Logger(ctx)
 	var id string
 	if h.Item != nil {
-		id = h.Item.(*api.CSIPluginListStub).ID
+		id = h.Item.(csiPluginListRow).ID
 	} else {
 		id = d.EqualsQualString("id")
 	}
@@ -165,11 +158,11 @@ func getPlugin(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData)
 		return nil, err
 	}
 
-	plugin, _, err := client.CSIPlugins().Info(id, &api.QueryOptions{})
+	plugin, meta, err := client.CSIPlugins().Info(id, queryOptions(d))
 	if err != nil {
 		logger.Error("nomad_node.getPlugin", "api_error", err)
 		return nil, err
 	}
 
-	return plugin, nil
+	return csiPluginRow{CSIPlugin: *plugin, queryMetaRow: queryMetaRow{meta: meta}}, nil
 }
@@ -0,0 +1,52 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func TestListPlugins(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handlePages("/v1/plugins",
+		[]*api.CSIPluginListStub{{ID: "ebs", Provider: "ebs.csi.aws.com", NodesHealthy: 3, NodesExpected: 3}},
+		[]*api.CSIPluginListStub{{ID: "efs", Provider: "efs.csi.aws.com", ControllerRequired: true}},
+	)
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{table: "nomad_plugin", columns: []string{"id", "provider", "nodes_healthy", "controller_required"}}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 rows across both pages", len(rows))
+	}
+	ids := map[string]bool{}
+	for _, row := range rows {
+		ids[row.string("id")] = true
+		if row.string("id") == "ebs" && (row.string("provider") != "ebs.csi.aws.com" || row.int("nodes_healthy") != 3) {
+			t.Errorf("unexpected ebs row: %v", row)
+		}
+	}
+	if !ids["ebs"] || !ids["efs"] {
+		t.Errorf("got plugin IDs %v, want ebs and efs", ids)
+	}
+
+	requests := f.requestsTo("/v1/plugins")
+	if len(requests) == 0 || requests[0].URL.Query().Get("type") != "csi" {
+		t.Errorf("expected CSI plugins to be listed")
+	}
+}
+
+func TestGetPlugin(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/plugin/csi/ebs", &api.CSIPlugin{ID: "ebs", Provider: "ebs.csi.aws.com", Version: "1.2.0"})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_plugin",
+		columns: []string{"id", "provider", "version"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"id": stringQual("ebs")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].string("id") != "ebs" || rows[0].string("version") != "1.2.0" {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+}
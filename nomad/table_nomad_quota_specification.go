@@ -0,0 +1,175 @@
+package nomad
+
+import (
+	"context"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+type quotaSpecificationLimitInfo struct {
+	Name           string
+	Description    string
+	Region         string
+	CPU            *int
+	Cores          *int
+	MemoryMB       *int
+	MemoryMaxMB    *int
+	VariablesLimit *int
+	RegionLimit    *api.Resources
+	CreateIndex    uint64
+	ModifyIndex    uint64
+}
+
+func tableNomadQuotaSpecification(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_quota_specification",
+		Description: "Retrieve the limits of your resource quotas, with a row per region. Requires Nomad Enterprise.",
+		List: &plugin.ListConfig{
+			Hydrate:      listQuotaSpecifications,
+			IgnoreConfig: enterpriseOnlyIgnoreConfig(),
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "name",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the quota specification.",
+			},
+			{
+				Name:        "description",
+				Type:        proto.ColumnType_STRING,
+				Description: "The description of the quota specification.",
+			},
+			{
+				Name:        "region",
+				Type:        proto.ColumnType_STRING,
+				Description: "The region the limit applies to. Null for quota specifications without limits.",
+				Transform:   transform.FromField("Region").NullIfZero(),
+			},
+			{
+				Name:        "cpu",
+				Type:        proto.ColumnType_INT,
+				Description: "The limit of the CPU in MHz, 0 meaning unlimited and -1 meaning none allowed.",
+				Transform:   transform.FromField("CPU"),
+			},
+			{
+				Name:        "cores",
+				Type:        proto.ColumnType_INT,
+				Description: "The limit of the reserved CPU cores, 0 meaning unlimited and -1 meaning none allowed.",
+			},
+			{
+				Name:        "memory_mb",
+				Type:        proto.ColumnType_INT,
+				Description: "The limit of the memory in MB, 0 meaning unlimited and -1 meaning none allowed.",
+				Transform:   transform.FromField("MemoryMB"),
+			},
+			{
+				Name:        "memory_max_mb",
+				Type:        proto.ColumnType_INT,
+				Description: "The limit of the maximum memory in MB, for tasks allowed to use more than their reserved memory, 0 meaning unlimited and -1 meaning none allowed.",
+				Transform:   transform.FromField("MemoryMaxMB"),
+			},
+			{
+				Name:        "variables_limit",
+				Type:        proto.ColumnType_INT,
+				Description: "The limit of the size of the variables in MB, 0 meaning unlimited and -1 meaning none allowed.",
+			},
+			{
+				Name:        "region_limit",
+				Type:        proto.ColumnType_JSON,
+				Description: "The resources the limit applies to, including the devices and networks.",
+			},
+			{
+				Name:        "create_index",
+				Type:        proto.ColumnType_INT,
+				Description: "Create index of the quota specification.",
+			},
+			{
+				Name:        "modify_index",
+				Type:        proto.ColumnType_INT,
+				Description: "Modify index of the quota specification.",
+			},
+
+			/// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "The title of the quota specification.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Name"),
+			},
+		},
+	}
+}
+
+func listQuotaSpecifications(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	client, err := getClient(ctx, d)
+	if err != nil {
+		logger.Error("nomad_quota_specification.listQuotaSpecifications", "connection_error", err)
+		return nil, err
+	}
+
+	// Fetch the quota specification directly if the name has been provided
+	if d.EqualsQualString("name") != "" {
+		quota, _, err := client.Quotas().Info(d.EqualsQualString("name"), queryOptions(d))
+		if err != nil {
+			logger.Error("nomad_quota_specification.listQuotaSpecifications", "api_error", err)
+			return nil, err
+		}
+		streamQuotaSpecificationLimits(ctx, d, quota)
+		return nil, nil
+	}
+
+	streamLimits := func(quota *api.QuotaSpec) (bool, error) {
+		return streamQuotaSpecificationLimits(ctx, d, quota), nil
+	}
+	if err := listPages(ctx, d, "nomad_quota_specification.listQuotaSpecifications", "quota specifications", queryOptions(d), client.Quotas().List, streamLimits); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// streamQuotaSpecificationLimits streams a row for each region limit of the
+// quota specification, or a single row if it has no limits, and returns false
+// once the limit of the query is reached.
+func streamQuotaSpecificationLimits(ctx context.Context, d *plugin.QueryData, quota *api.QuotaSpec) bool {
+	limits := quota.Limits
+	if len(limits) == 0 {
+		limits = []*api.QuotaLimit{{}}
+	}
+
+	for _, limit := range limits {
+		row := quotaSpecificationLimitInfo{
+			Name:           quota.Name,
+			Description:    quota.Description,
+			Region:         limit.Region,
+			VariablesLimit: limit.VariablesLimit,
+			RegionLimit:    limit.RegionLimit,
+			CreateIndex:    quota.CreateIndex,
+			ModifyIndex:    quota.ModifyIndex,
+		}
+		if limit.RegionLimit != nil {
+			row.CPU = limit.RegionLimit.CPU
+			row.Cores = limit.RegionLimit.Cores
+			row.MemoryMB = limit.RegionLimit.MemoryMB
+			row.MemoryMaxMB = limit.RegionLimit.MemoryMaxMB
+		}
+		d.StreamListItem(ctx, row)
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return false
+		}
+	}
+	return true
+}
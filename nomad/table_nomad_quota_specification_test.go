@@ -0,0 +1,105 @@
+package nomad
+
+import (
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func testQuotaSpec() *api.QuotaSpec {
+	return &api.QuotaSpec{
+		Name:        "team",
+		Description: "Limits of the team",
+		Limits: []*api.QuotaLimit{
+			{
+				Region:         "eu",
+				RegionLimit:    &api.Resources{CPU: pointerOf(2500), MemoryMB: pointerOf(1000)},
+				VariablesLimit: pointerOf(10),
+			},
+			{
+				Region:      "us",
+				RegionLimit: &api.Resources{CPU: pointerOf(-1), Cores: pointerOf(2)},
+			},
+		},
+	}
+}
+
+func TestListQuotaSpecifications(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/quotas", []*api.QuotaSpec{testQuotaSpec(), {Name: "empty"}})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_quota_specification",
+		columns: []string{"name", "description", "region", "cpu", "cores", "memory_mb", "variables_limit"},
+	}.mustExecute(t, server)
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want a row per region limit and one for the quota without limits", len(rows))
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].string("name")+rows[i].string("region") < rows[j].string("name")+rows[j].string("region")
+	})
+
+	if !rows[0].isNull("region") || !rows[0].isNull("cpu") {
+		t.Errorf("want a row without limits for the empty quota: %v", rows[0])
+	}
+	eu := rows[1]
+	if eu.string("region") != "eu" || eu.int("cpu") != 2500 || eu.int("memory_mb") != 1000 || eu.int("variables_limit") != 10 || !eu.isNull("cores") {
+		t.Errorf("unexpected eu row: %v", eu)
+	}
+	us := rows[2]
+	if us.string("region") != "us" || us.int("cpu") != -1 || us.int("cores") != 2 || !us.isNull("memory_mb") {
+		t.Errorf("unexpected us row: %v", us)
+	}
+}
+
+func TestListQuotaSpecificationsByName(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/quota/team", testQuotaSpec())
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_quota_specification",
+		columns: []string{"name", "region"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"name": stringQual("team")}),
+	}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Errorf("got %d rows, want a row per region limit", len(rows))
+	}
+	if len(f.requestsTo("/v1/quotas")) != 0 {
+		t.Errorf("want the quotas not to be listed when the name is set")
+	}
+
+	rows = testQuery{
+		table:   "nomad_quota_specification",
+		columns: []string{"name"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"name": stringQual("missing")}),
+	}.mustExecute(t, server)
+	if len(rows) != 0 {
+		t.Errorf("got %d rows for a missing quota, want none", len(rows))
+	}
+}
+
+func TestListQuotaSpecificationsWithoutEnterprise(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handleError("/v1/quotas", http.StatusNotImplemented, "Nomad Enterprise only endpoint")
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{table: "nomad_quota_specification", columns: []string{"name"}}.mustExecute(t, server)
+	if len(rows) != 0 {
+		t.Errorf("got %d rows, want none on clusters without quotas", len(rows))
+	}
+}
+
+func TestListQuotaSpecificationsError(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handleError("/v1/quotas", http.StatusForbidden, "Permission denied")
+	server := newTestPluginServer(t, f, "")
+
+	if _, err := (testQuery{table: "nomad_quota_specification", columns: []string{"name"}}).execute(t, server); err == nil {
+		t.Errorf("want the permission error to be returned")
+	}
+}
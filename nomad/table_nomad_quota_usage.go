@@ -0,0 +1,229 @@
+package nomad
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+type quotaUsageInfo struct {
+	Name             string
+	Region           string
+	CPUUsed          *int
+	CPULimit         *int
+	CoresUsed        *int
+	CoresLimit       *int
+	MemoryMBUsed     *int
+	MemoryMBLimit    *int
+	MemoryMaxMBUsed  *int
+	MemoryMaxMBLimit *int
+	VariablesUsed    *int
+	VariablesLimit   *int
+	CreateIndex      uint64
+	ModifyIndex      uint64
+}
+
+func tableNomadQuotaUsage(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_quota_usage",
+		Description: "Retrieve the resources used by your resource quotas against their limits, with a row per region. Requires Nomad Enterprise.",
+		List: &plugin.ListConfig{
+			Hydrate:      listQuotaUsages,
+			IgnoreConfig: enterpriseOnlyIgnoreConfig(),
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "name",
+					Require: plugin.Optional,
+				},
+			},
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the quota specification.",
+			},
+			{
+				Name:        "region",
+				Type:        proto.ColumnType_STRING,
+				Description: "The region the usage applies to.",
+			},
+			{
+				Name:        "cpu_used",
+				Type:        proto.ColumnType_INT,
+				Description: "The CPU in MHz used by the allocations of the namespaces using the quota.",
+				Transform:   transform.FromField("CPUUsed"),
+			},
+			{
+				Name:        "cpu_limit",
+				Type:        proto.ColumnType_INT,
+				Description: "The limit of the CPU in MHz, 0 meaning unlimited and -1 meaning none allowed.",
+				Transform:   transform.FromField("CPULimit"),
+			},
+			{
+				Name:        "cores_used",
+				Type:        proto.ColumnType_INT,
+				Description: "The reserved CPU cores used by the allocations of the namespaces using the quota.",
+			},
+			{
+				Name:        "cores_limit",
+				Type:        proto.ColumnType_INT,
+				Description: "The limit of the reserved CPU cores, 0 meaning unlimited and -1 meaning none allowed.",
+			},
+			{
+				Name:        "memory_mb_used",
+				Type:        proto.ColumnType_INT,
+				Description: "The memory in MB used by the allocations of the namespaces using the quota.",
+				Transform:   transform.FromField("MemoryMBUsed"),
+			},
+			{
+				Name:        "memory_mb_limit",
+				Type:        proto.ColumnType_INT,
+				Description: "The limit of the memory in MB, 0 meaning unlimited and -1 meaning none allowed.",
+				Transform:   transform.FromField("MemoryMBLimit"),
+			},
+			{
+				Name:        "memory_max_mb_used",
+				Type:        proto.ColumnType_INT,
+				Description: "The maximum memory in MB used by the allocations of the namespaces using the quota.",
+				Transform:   transform.FromField("MemoryMaxMBUsed"),
+			},
+			{
+				Name:        "memory_max_mb_limit",
+				Type:        proto.ColumnType_INT,
+				Description: "The limit of the maximum memory in MB, 0 meaning unlimited and -1 meaning none allowed.",
+				Transform:   transform.FromField("MemoryMaxMBLimit"),
+			},
+			{
+				Name:        "variables_used",
+				Type:        proto.ColumnType_INT,
+				Description: "The size of the variables in MB of the namespaces using the quota.",
+			},
+			{
+				Name:        "variables_limit",
+				Type:        proto.ColumnType_INT,
+				Description: "The limit of the size of the variables in MB, 0 meaning unlimited and -1 meaning none allowed.",
+			},
+			{
+				Name:        "create_index",
+				Type:        proto.ColumnType_INT,
+				Description: "Create index of the quota usage.",
+			},
+			{
+				Name:        "modify_index",
+				Type:        proto.ColumnType_INT,
+				Description: "Modify index of the quota usage.",
+			},
+
+			/// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "The title of the quota usage.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Name"),
+			},
+		},
+	}
+}
+
+func listQuotaUsages(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+
+	client, err := getClient(ctx, d)
+	if err != nil {
+		logger.Error("nomad_quota_usage.listQuotaUsages", "connection_error", err)
+		return nil, err
+	}
+
+	// Fetch the quota usage directly if the name has been provided
+	if name := d.EqualsQualString("name"); name != "" {
+		usage, _, err := client.Quotas().Usage(name, queryOptions(d))
+		if err != nil {
+			logger.Error("nomad_quota_usage.listQuotaUsages", "api_error", err)
+			return nil, err
+		}
+		quota, _, err := client.Quotas().Info(name, queryOptions(d))
+		if err != nil {
+			logger.Error("nomad_quota_usage.listQuotaUsages", "api_error", err)
+			return nil, err
+		}
+		streamQuotaUsage(ctx, d, usage, quota)
+		return nil, nil
+	}
+
+	// The usages only hold the resources used, so the limits are read from the
+	// quota specifications
+	quotas := map[string]*api.QuotaSpec{}
+	collectQuotas := func(quota *api.QuotaSpec) (bool, error) {
+		quotas[quota.Name] = quota
+		return true, nil
+	}
+	if err := listPages(ctx, d, "nomad_quota_usage.listQuotaUsages", "quota specifications", queryOptions(d), client.Quotas().List, collectQuotas); err != nil {
+		return nil, err
+	}
+
+	streamUsage := func(usage *api.QuotaUsage) (bool, error) {
+		return streamQuotaUsage(ctx, d, usage, quotas[usage.Name]), nil
+	}
+	if err := listPages(ctx, d, "nomad_quota_usage.listQuotaUsages", "quota usages", queryOptions(d), client.Quotas().ListUsage, streamUsage); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// streamQuotaUsage streams a row for each region the quota is used in, along
+// with the limits of the quota specification in that region, and returns false
+// once the limit of the query is reached.
+func streamQuotaUsage(ctx context.Context, d *plugin.QueryData, usage *api.QuotaUsage, quota *api.QuotaSpec) bool {
+	limits := map[string]*api.QuotaLimit{}
+	if quota != nil {
+		for _, limit := range quota.Limits {
+			limits[limit.Region] = limit
+		}
+	}
+
+	used := make([]*api.QuotaLimit, 0, len(usage.Used))
+	for _, limit := range usage.Used {
+		used = append(used, limit)
+	}
+	sort.Slice(used, func(i, j int) bool {
+		return used[i].Region < used[j].Region
+	})
+
+	for _, u := range used {
+		row := quotaUsageInfo{
+			Name:          usage.Name,
+			Region:        u.Region,
+			VariablesUsed: u.VariablesLimit,
+			CreateIndex:   usage.CreateIndex,
+			ModifyIndex:   usage.ModifyIndex,
+		}
+		if u.RegionLimit != nil {
+			row.CPUUsed = u.RegionLimit.CPU
+			row.CoresUsed = u.RegionLimit.Cores
+			row.MemoryMBUsed = u.RegionLimit.MemoryMB
+			row.MemoryMaxMBUsed = u.RegionLimit.MemoryMaxMB
+		}
+		if limit := limits[u.Region]; limit != nil {
+			row.VariablesLimit = limit.VariablesLimit
+			if limit.RegionLimit != nil {
+				row.CPULimit = limit.RegionLimit.CPU
+				row.CoresLimit = limit.RegionLimit.Cores
+				row.MemoryMBLimit = limit.RegionLimit.MemoryMB
+				row.MemoryMaxMBLimit = limit.RegionLimit.MemoryMaxMB
+			}
+		}
+		d.StreamListItem(ctx, row)
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package nomad
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func testQuotaUsage() *api.QuotaUsage {
+	return &api.QuotaUsage{
+		Name: "team",
+		Used: map[string]*api.QuotaLimit{
+			"aGFzaC1ldQ==": {
+				Region:         "eu",
+				RegionLimit:    &api.Resources{CPU: pointerOf(1200), MemoryMB: pointerOf(256)},
+				VariablesLimit: pointerOf(1),
+			},
+			"aGFzaC11cw==": {
+				Region:      "us",
+				RegionLimit: &api.Resources{CPU: pointerOf(0), Cores: pointerOf(1)},
+			},
+		},
+	}
+}
+
+func TestListQuotaUsages(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/quotas", []*api.QuotaSpec{testQuotaSpec()})
+	f.handle("/v1/quota-usages", []*api.QuotaUsage{testQuotaUsage()})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_quota_usage",
+		columns: []string{"name", "region", "cpu_used", "cpu_limit", "cores_used", "cores_limit", "memory_mb_used", "memory_mb_limit", "variables_used", "variables_limit"},
+	}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want a row per region", len(rows))
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].string("region") < rows[j].string("region") })
+
+	eu := rows[0]
+	if eu.string("name") != "team" || eu.string("region") != "eu" {
+		t.Errorf("unexpected eu row: %v", eu)
+	}
+	if eu.int("cpu_used") != 1200 || eu.int("cpu_limit") != 2500 || eu.int("memory_mb_used") != 256 || eu.int("memory_mb_limit") != 1000 {
+		t.Errorf("unexpected resources of the eu row: %v", eu)
+	}
+	if eu.int("variables_used") != 1 || eu.int("variables_limit") != 10 {
+		t.Errorf("unexpected variables of the eu row: %v", eu)
+	}
+	us := rows[1]
+	if us.int("cores_used") != 1 || us.int("cores_limit") != 2 || us.int("cpu_limit") != -1 || !us.isNull("memory_mb_limit") {
+		t.Errorf("unexpected us row: %v", us)
+	}
+}
+
+func TestListQuotaUsagesByName(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/quota/team", testQuotaSpec())
+	f.handle("/v1/quota/usage/team", testQuotaUsage())
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_quota_usage",
+		columns: []string{"region", "cpu_limit"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"name": stringQual("team")}),
+	}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Errorf("got %d rows, want a row per region", len(rows))
+	}
+	if len(f.requestsTo("/v1/quota-usages")) != 0 || len(f.requestsTo("/v1/quotas")) != 0 {
+		t.Errorf("want the quotas not to be listed when the name is set")
+	}
+}
+
+func TestListQuotaUsagesWithoutEnterprise(t *testing.T) {
+	// Clusters without the quota endpoints respond with a 404
+	f := newFakeNomad(t)
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{table: "nomad_quota_usage", columns: []string{"name"}}.mustExecute(t, server)
+	if len(rows) != 0 {
+		t.Errorf("got %d rows, want none on clusters without quotas", len(rows))
+	}
+}
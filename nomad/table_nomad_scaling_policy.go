@@ -0,0 +1,197 @@
+package nomad
+
+import (
+	"context"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+type scalingPolicyListRow struct {
+	api.ScalingPolicyListStub
+	queryMetaRow
+}
+
+type scalingPolicyRow struct {
+	api.ScalingPolicy
+	queryMetaRow
+}
+
+func tableNomadScalingPolicy(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_scaling_policy",
+		Description: "Retrieve information about the scaling policies of your jobs.",
+		List: &plugin.ListConfig{
+			Hydrate: listScalingPolicies,
+			KeyColumns: []*plugin.KeyColumn{
+				{
+					Name:    "namespace",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "job_id",
+					Require: plugin.Optional,
+				},
+				{
+					Name:    "type",
+					Require: plugin.Optional,
+				},
+				consistencyKeyColumn(),
+			},
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns: append(plugin.SingleColumn("id"), consistencyKeyColumn()),
+			Hydrate:    getScalingPolicy,
+		},
+		Columns: queryMetaColumns([]*plugin.Column{
+			{
+				Name:        "id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the scaling policy.",
+				Transform:   transform.FromField("ID"),
+			},
+			{
+				Name:        "type",
+				Type:        proto.ColumnType_STRING,
+				Description: "The type of the scaling policy, either horizontal, or vertical_cpu or vertical_mem in Nomad Enterprise.",
+			},
+			{
+				Name:        "enabled",
+				Type:        proto.ColumnType_BOOL,
+				Description: "Whether the autoscaler acts on the scaling policy.",
+			},
+			{
+				Name:        "namespace",
+				Type:        proto.ColumnType_STRING,
+				Description: "The namespace of the job the scaling policy targets.",
+				Transform:   transform.FromField("Target.Namespace"),
+			},
+			{
+				Name:        "job_id",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ID of the job the scaling policy targets.",
+				Transform:   transform.FromField("Target.Job"),
+			},
+			{
+				Name:        "task_group",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the task group the scaling policy targets.",
+				Transform:   transform.FromField("Target.Group"),
+			},
+			{
+				Name:        "task",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the task the scaling policy targets, for vertical scaling policies.",
+				Transform:   transform.FromField("Target.Task").NullIfZero(),
+			},
+			{
+				Name:        "min",
+				Type:        proto.ColumnType_INT,
+				Description: "The lower bound of the count, or of the resource for vertical scaling policies, the autoscaler can scale to.",
+				Hydrate:     getScalingPolicy,
+			},
+			{
+				Name:        "max",
+				Type:        proto.ColumnType_INT,
+				Description: "The upper bound of the count, or of the resource for vertical scaling policies, the autoscaler can scale to.",
+				Hydrate:     getScalingPolicy,
+			},
+			{
+				Name:        "policy",
+				Type:        proto.ColumnType_JSON,
+				Description: "The policy evaluated by the autoscaler, such as its checks, strategies and cooldown.",
+				Hydrate:     getScalingPolicy,
+			},
+			{
+				Name:        "target",
+				Type:        proto.ColumnType_JSON,
+				Description: "The target of the scaling policy.",
+			},
+			{
+				Name:        "create_index",
+				Type:        proto.ColumnType_INT,
+				Description: "Create index of the scaling policy.",
+			},
+			{
+				Name:        "modify_index",
+				Type:        proto.ColumnType_INT,
+				Description: "Modify index of the scaling policy.",
+			},
+
+			/// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "The title of the scaling policy.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ID"),
+			},
+		}),
+	}
+}
+
+func listScalingPolicies(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	client, err := getClient(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("nomad_scaling_policy.listScalingPolicies", "connection_error", err)
+		return nil, err
+	}
+
+	input := queryOptions(d)
+	input.PerPage = pageSize(d, "namespace", "job_id", "type")
+	input.Params = map[string]string{}
+
+	if d.EqualsQualString("namespace") != "" {
+		input.Namespace = d.EqualsQualString("namespace")
+	}
+	if d.EqualsQualString("job_id") != "" {
+		input.Params["job"] = d.EqualsQualString("job_id")
+	}
+	if d.EqualsQualString("type") != "" {
+		input.Params["type"] = d.EqualsQualString("type")
+	}
+
+	if err := streamPages(ctx, d, "nomad_scaling_policy.listScalingPolicies", "scaling policies", input, client.Scaling().ListPolicies, func(item *api.ScalingPolicyListStub, meta *api.QueryMeta) interface{} {
+		return scalingPolicyListRow{ScalingPolicyListStub: *item, queryMetaRow: queryMetaRow{meta: meta}}
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getScalingPolicy(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	input := queryOptions(d)
+	var id string
+	if h.Item != nil {
+		// The policy is read in the namespace of the job it targets, as the
+		// policies are listed across the namespaces of the connection
+		stub := h.Item.(scalingPolicyListRow)
+		id = stub.ID
+		input.Namespace = stub.Target["Namespace"]
+	} else {
+		id = d.EqualsQualString("id")
+	}
+
+	// check if id is empty
+	if id == "" {
+		return nil, nil
+	}
+
+	// Create client
+	client, err := getClient(ctx, d)
+	if err != nil {
+		logger.Error("nomad_scaling_policy.getScalingPolicy", "connection_error", err)
+		return nil, err
+	}
+
+	policy, meta, err := client.Scaling().GetPolicy(id, input)
+	if err != nil {
+		logger.Error("nomad_scaling_policy.getScalingPolicy", "api_error", err)
+		return nil, err
+	}
+
+	return scalingPolicyRow{ScalingPolicy: *policy, queryMetaRow: queryMetaRow{meta: meta}}, nil
+}
@@ -0,0 +1,89 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func testScalingPolicy() *api.ScalingPolicy {
+	return &api.ScalingPolicy{
+		ID:        "policy-1",
+		Type:      api.ScalingPolicyTypeHorizontal,
+		Namespace: "apps",
+		Target:    map[string]string{"Namespace": "apps", "Job": "web", "Group": "frontend"},
+		Min:       pointerOf(int64(1)),
+		Max:       pointerOf(int64(10)),
+		Enabled:   pointerOf(true),
+		Policy:    map[string]interface{}{"cooldown": "1m"},
+	}
+}
+
+func TestListScalingPolicies(t *testing.T) {
+	f := newFakeNomad(t)
+	policy := testScalingPolicy()
+	f.handle("/v1/scaling/policies", []*api.ScalingPolicyListStub{{ID: policy.ID, Type: policy.Type, Enabled: true, Target: policy.Target}})
+	f.handle("/v1/scaling/policy/policy-1", policy)
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_scaling_policy",
+		columns: []string{"id", "type", "enabled", "namespace", "job_id", "task_group", "task", "min", "max", "policy"},
+	}.mustExecute(t, server)
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(rows))
+	}
+	row := rows[0]
+	if row.string("type") != "horizontal" || !row.bool("enabled") || row.string("namespace") != "apps" || row.string("job_id") != "web" || row.string("task_group") != "frontend" || !row.isNull("task") {
+		t.Errorf("unexpected target of the policy: %v", row)
+	}
+	if row.int("min") != 1 || row.int("max") != 10 || row.json("policy") != `{"cooldown":"1m"}` {
+		t.Errorf("unexpected bounds of the policy: %v", row)
+	}
+
+	// The policy is read in the namespace of its job
+	if requests := f.requestsTo("/v1/scaling/policy/policy-1"); len(requests) != 1 || requests[0].URL.Query().Get("namespace") != "apps" {
+		t.Errorf("want the policy to be read in the apps namespace")
+	}
+}
+
+func TestListScalingPoliciesPushesDownQuals(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/scaling/policies", []*api.ScalingPolicyListStub{})
+	server := newTestPluginServer(t, f, "")
+
+	testQuery{
+		table:   "nomad_scaling_policy",
+		columns: []string{"id"},
+		quals: equalsQuals(map[string]*proto.QualValue{
+			"namespace": stringQual("apps"),
+			"job_id":    stringQual("web"),
+			"type":      stringQual("horizontal"),
+		}),
+	}.mustExecute(t, server)
+
+	requests := f.requestsTo("/v1/scaling/policies")
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	query := requests[0].URL.Query()
+	if query.Get("namespace") != "apps" || query.Get("job") != "web" || query.Get("type") != "horizontal" {
+		t.Errorf("got query %s, want the quals to be pushed down", query.Encode())
+	}
+}
+
+func TestGetScalingPolicy(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/scaling/policy/policy-1", testScalingPolicy())
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_scaling_policy",
+		columns: []string{"id", "job_id", "max"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"id": stringQual("policy-1")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].string("job_id") != "web" || rows[0].int("max") != 10 {
+		t.Errorf("got rows %v, want the policy", rows)
+	}
+}
@@ -0,0 +1,134 @@
+package nomad
+
+import (
+	"context"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+type sentinelPolicyListRow struct {
+	api.SentinelPolicyListStub
+	queryMetaRow
+}
+
+type sentinelPolicyRow struct {
+	api.SentinelPolicy
+	queryMetaRow
+}
+
+func tableNomadSentinelPolicy(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "nomad_sentinel_policy",
+		Description: "Retrieve information about your Sentinel policies. Requires Nomad Enterprise.",
+		List: &plugin.ListConfig{
+			Hydrate:      listSentinelPolicies,
+			IgnoreConfig: enterpriseOnlyIgnoreConfig(),
+			KeyColumns: []*plugin.KeyColumn{
+				consistencyKeyColumn(),
+			},
+		},
+		Get: &plugin.GetConfig{
+			KeyColumns:   append(plugin.SingleColumn("name"), consistencyKeyColumn()),
+			Hydrate:      getSentinelPolicy,
+			IgnoreConfig: enterpriseOnlyIgnoreConfig(),
+		},
+		Columns: queryMetaColumns([]*plugin.Column{
+			{
+				Name:        "name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the sentinel policy.",
+			},
+			{
+				Name:        "description",
+				Type:        proto.ColumnType_STRING,
+				Description: "The description of the sentinel policy.",
+			},
+			{
+				Name:        "scope",
+				Type:        proto.ColumnType_STRING,
+				Description: "The scope the sentinel policy is evaluated in, such as submit-job or submit-host-volume.",
+			},
+			{
+				Name:        "enforcement_level",
+				Type:        proto.ColumnType_STRING,
+				Description: "The enforcement level of the sentinel policy, either advisory, soft-mandatory or hard-mandatory.",
+			},
+			{
+				Name:        "policy",
+				Type:        proto.ColumnType_STRING,
+				Description: "The source of the sentinel policy.",
+				Hydrate:     getSentinelPolicy,
+			},
+			{
+				Name:        "create_index",
+				Type:        proto.ColumnType_INT,
+				Description: "The index when the sentinel policy was created.",
+			},
+			{
+				Name:        "modify_index",
+				Type:        proto.ColumnType_INT,
+				Description: "The index when the sentinel policy was last modified.",
+			},
+
+			/// Steampipe standard columns
+			{
+				Name:        "title",
+				Description: "The title of the sentinel policy.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Name"),
+			},
+		}),
+	}
+}
+
+func listSentinelPolicies(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	client, err := getClient(ctx, d)
+	if err != nil {
+		plugin.Logger(ctx).Error("nomad_sentinel_policy.listSentinelPolicies", "connection_error", err)
+		return nil, err
+	}
+
+	input := queryOptions(d)
+	input.PerPage = pageSize(d)
+
+	if err := streamPages(ctx, d, "nomad_sentinel_policy.listSentinelPolicies", "Sentinel policies", input, client.SentinelPolicies().List, func(item *api.SentinelPolicyListStub, meta *api.QueryMeta) interface{} {
+		return sentinelPolicyListRow{SentinelPolicyListStub: *item, queryMetaRow: queryMetaRow{meta: meta}}
+	}); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getSentinelPolicy(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	var name string
+	if h.Item != nil {
+		name = h.Item.(sentinelPolicyListRow).Name
+	} else {
+		name = d.EqualsQualString("name")
+	}
+
+	// check if name is empty
+	if name == "" {
+		return nil, nil
+	}
+
+	// Create client
+	client, err := getClient(ctx, d)
+	if err != nil {
+		logger.Error("nomad_sentinel_policy.getSentinelPolicy", "connection_error", err)
+		return nil, err
+	}
+
+	policy, meta, err := client.SentinelPolicies().Info(name, queryOptions(d))
+	if err != nil {
+		logger.Error("nomad_sentinel_policy.getSentinelPolicy", "api_error", err)
+		return nil, err
+	}
+
+	return sentinelPolicyRow{SentinelPolicy: *policy, queryMetaRow: queryMetaRow{meta: meta}}, nil
+}
@@ -0,0 +1,73 @@
+package nomad
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+const testSentinelPolicy = `main = rule { all job.task_groups as tg { tg.count <= 10 } }`
+
+func TestListSentinelPolicies(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handlePages("/v1/sentinel/policies",
+		[]*api.SentinelPolicyListStub{{Name: "max-count", Scope: "submit-job", EnforcementLevel: "hard-mandatory"}},
+		[]*api.SentinelPolicyListStub{{Name: "advice", Scope: "submit-job", EnforcementLevel: "advisory"}},
+	)
+	f.handle("/v1/sentinel/policy/max-count", &api.SentinelPolicy{Name: "max-count", Scope: "submit-job", EnforcementLevel: "hard-mandatory", Policy: testSentinelPolicy})
+	f.handle("/v1/sentinel/policy/advice", &api.SentinelPolicy{Name: "advice", Scope: "submit-job", EnforcementLevel: "advisory", Policy: "main = rule { true }"})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{table: "nomad_sentinel_policy", columns: []string{"name", "scope", "enforcement_level", "policy"}}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 rows across both pages", len(rows))
+	}
+	for _, row := range rows {
+		if row.string("name") == "max-count" && (row.string("policy") != testSentinelPolicy || row.string("enforcement_level") != "hard-mandatory") {
+			t.Errorf("unexpected max-count row: %v", row)
+		}
+	}
+}
+
+func TestGetSentinelPolicy(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/sentinel/policy/max-count", &api.SentinelPolicy{Name: "max-count", Policy: testSentinelPolicy})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_sentinel_policy",
+		columns: []string{"name", "policy"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"name": stringQual("max-count")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].string("policy") != testSentinelPolicy {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+	if len(f.requestsTo("/v1/sentinel/policies")) != 0 {
+		t.Errorf("expected the policy to be fetched without listing policies")
+	}
+}
+
+func TestListSentinelPoliciesWithoutEnterprise(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusNotImplemented} {
+		f := newFakeNomad(t)
+		f.handleError("/v1/sentinel/policies", status, "Nomad Enterprise only endpoint")
+		server := newTestPluginServer(t, f, "")
+
+		rows := testQuery{table: "nomad_sentinel_policy", columns: []string{"name"}}.mustExecute(t, server)
+		if len(rows) != 0 {
+			t.Errorf("got %d rows for status %d, want none on clusters without Sentinel", len(rows), status)
+		}
+	}
+}
+
+func TestListSentinelPoliciesError(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handleError("/v1/sentinel/policies", http.StatusForbidden, "Permission denied")
+	server := newTestPluginServer(t, f, "")
+
+	if _, err := (testQuery{table: "nomad_sentinel_policy", columns: []string{"name"}}).execute(t, server); err == nil {
+		t.Errorf("want the permission error to be returned")
+	}
+}
@@ -10,6 +10,16 @@ import (
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
 )
 
+type csiVolumeListRow struct {
+	api.CSIVolumeListStub
+	queryMetaRow
+}
+
+type csiVolumeRow struct {
+	api.CSIVolume
+	queryMetaRow
+}
+
 func tableNomadVolume(ctx context.Context) *plugin.Table {
 	return &plugin.Table{
 		Name:        "nomad_volume",
@@ -25,13 +35,20 @@ func tableNomadVolume(ctx context.Context) *plugin.Table {
 					Name:    "name",
 					Require: plugin.Optional,
 				},
+				consistencyKeyColumn(),
 			},
 		},
 		Get: &plugin.GetConfig{
-			KeyColumns: plugin.SingleColumn("id"),
+			KeyColumns: append(plugin.SingleColumn("id"), consistencyKeyColumn()),
 			Hydrate:    getVolume,
 		},
-		Columns: []*plugin.Column{
+		HydrateConfig: []plugin.HydrateConfig{
+			{
+				Func:    getVolumeSecrets,
+				Depends: []plugin.HydrateFunc{getVolume},
+			},
+		},
+		Columns: queryMetaColumns([]*plugin.Column{
 			{
 				Name:        "id",
 				Type:        proto.ColumnType_STRING,
@@ -106,6 +123,7 @@ func tableNomadVolume(ctx context.Context) *plugin.Table {
 				Name:        "provider_version",
 				Type:        proto.ColumnType_STRING,
 				Description: "The version of the provider of the CSI volume.",
+				Hydrate:     getVolume,
 			},
 			{
 				Name:        "controller_required",
@@ -151,6 +169,7 @@ func tableNomadVolume(ctx context.Context) *plugin.Table {
 				Name:        "requested_topologies",
 				Type:        proto.ColumnType_JSON,
 				Description: "The topologies that were submitted as options to the storage provider at the time the volume was created.",
+				Hydrate:     getVolume,
 			},
 			{
 				Name:        "topologies",
@@ -176,8 +195,9 @@ func tableNomadVolume(ctx context.Context) *plugin.Table {
 			{
 				Name:        "secrets",
 				Type:        proto.ColumnType_JSON,
-				Description: "The secrets of the CSI volume.",
-				Hydrate:     getVolume,
+				Description: "The secrets of the CSI volume. The values are redacted to fingerprints unless reveal_secrets is set in the connection config.",
+				Hydrate:     getVolumeSecrets,
+				Transform:   transform.FromValue(),
 			},
 			{
 				Name:        "parameters",
@@ -195,6 +215,7 @@ func tableNomadVolume(ctx context.Context) *plugin.Table {
 				Name:        "requested_capabilities",
 				Type:        proto.ColumnType_JSON,
 				Description: "The requested capabilities of the CSI volume.",
+				Hydrate:     getVolume,
 			},
 			{
 				Name:        "read_allocs",
@@ -229,7 +250,7 @@ func tableNomadVolume(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromField("Name"),
 			},
-		},
+		}),
 	}
 }
 
@@ -240,16 +261,8 @@ func listVolumes(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData
 		return nil, err
 	}
 
-	maxLimit := int64(1000)
-	if d.QueryContext.Limit != nil {
-		if *d.QueryContext.Limit < maxLimit {
-			maxLimit = *d.QueryContext.Limit
-		}
-	}
-
-	input := &api.QueryOptions{
-		PerPage: int32(maxLimit),
-	}
+	input := queryOptions(d)
+	input.PerPage = pageSize(d, "namespace", "name")
 
 	if d.EqualsQualString("namespace") != "" {
 		input.Namespace = d.EqualsQualString("namespace")
@@ -259,25 +272,10 @@ func listVolumes(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData
 		input.Filter = filter
 	}
 
-	for {
-		volumes, metadata, err := client.CSIVolumes().List(input)
-		if err != nil {
-			plugin.Logger(ctx).Error("nomad_volume.listVolumes", "api_error", err)
-			return nil, err
-		}
-
-		for _, volume := range volumes {
-			d.StreamListItem(ctx, volume)
-
-			// Context can be cancelled due to manual cancellation or the limit has been hit
-			if d.RowsRemaining(ctx) == 0 {
-				return nil, nil
-			}
-		}
-		input.NextToken = metadata.NextToken
-		if input.NextToken == "" {
-			break
-		}
+	if err := streamPages(ctx, d, "nomad_volume.listVolumes", "CSI volumes", input, client.CSIVolumes().List, func(item *api.CSIVolumeListStub, meta *api.QueryMeta) interface{} {
+		return csiVolumeListRow{CSIVolumeListStub: *item, queryMetaRow: queryMetaRow{meta: meta}}
+	}); err != nil {
+		return nil, err
 	}
 
 	return nil, nil
@@ -287,7 +285,7 @@ func getVolume(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData)
 	logger := plugin.Logger(ctx)
 	var id string
 	if h.Item != nil {
-		id = h.Item.(*api.CSIVolumeListStub).ID
+		id = h.Item.(csiVolumeListRow).ID
 	} else {
 		id = d.EqualsQualString("id")
 	}
@@ -304,11 +302,11 @@ func getVolume(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData)
 		return nil, err
 	}
 
-	volume, _, err := client.CSIVolumes().Info(id, &api.QueryOptions{})
+	volume, meta, err := client.CSIVolumes().Info(id, queryOptions(d))
 	if err != nil {
 		logger.Error("nomad_node.getVolume", "api_error", err)
 		return nil, err
 	}
 
-	return volume, nil
+	return csiVolumeRow{CSIVolume: *volume, queryMetaRow: queryMetaRow{meta: meta}}, nil
 }
@@ -0,0 +1,98 @@
+package nomad
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+)
+
+func TestListVolumes(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handlePages("/v1/volumes",
+		[]*api.CSIVolumeListStub{{ID: "vol-1", Name: "data", Namespace: "default", PluginID: "ebs", Schedulable: true}},
+		[]*api.CSIVolumeListStub{{ID: "vol-2", Name: "logs", Namespace: "default", PluginID: "ebs"}},
+	)
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{table: "nomad_volume", columns: []string{"id", "name", "plugin_id", "schedulable"}}.mustExecute(t, server)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 rows across both pages", len(rows))
+	}
+	for _, row := range rows {
+		if row.string("id") == "vol-1" && (row.string("plugin_id") != "ebs" || !row.bool("schedulable")) {
+			t.Errorf("unexpected vol-1 row: %v", row)
+		}
+	}
+
+	requests := f.requestsTo("/v1/volumes")
+	if len(requests) == 0 || requests[0].URL.Query().Get("type") != "csi" {
+		t.Errorf("expected CSI volumes to be listed")
+	}
+}
+
+func TestListVolumesPushesDownQuals(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/volumes", []*api.CSIVolumeListStub{{ID: "vol-1", Name: "data", Namespace: "apps"}})
+	server := newTestPluginServer(t, f, "")
+
+	testQuery{
+		table:   "nomad_volume",
+		columns: []string{"id", "name", "namespace"},
+		quals: equalsQuals(map[string]*proto.QualValue{
+			"namespace": stringQual("apps"),
+			"name":      stringQual("data"),
+		}),
+	}.mustExecute(t, server)
+
+	requests := f.requestsTo("/v1/volumes")
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+	query := requests[0].URL.Query()
+	if query.Get("namespace") != "apps" {
+		t.Errorf("got namespace %q, want apps", query.Get("namespace"))
+	}
+	if filter := strings.TrimSpace(query.Get("filter")); filter != `Name== "data"` {
+		t.Errorf("got filter %q, want the name filter", filter)
+	}
+}
+
+func TestGetVolume(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/volume/csi/vol-1", &api.CSIVolume{
+		ID:         "vol-1",
+		Name:       "data",
+		ExternalID: "vol-0abc",
+		Secrets:    api.CSISecrets{"password": "hunter2"},
+	})
+	server := newTestPluginServer(t, f, "")
+
+	rows := testQuery{
+		table:   "nomad_volume",
+		columns: []string{"id", "external_id", "secrets"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"id": stringQual("vol-1")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].string("external_id") != "vol-0abc" {
+		t.Fatalf("unexpected rows: %v", rows)
+	}
+	if secrets := rows[0].json("secrets"); strings.Contains(secrets, "hunter2") || !strings.Contains(secrets, redactedSecretPrefix) {
+		t.Errorf("got secrets %s, want them redacted", secrets)
+	}
+}
+
+func TestGetVolumeRevealSecrets(t *testing.T) {
+	f := newFakeNomad(t)
+	f.handle("/v1/volume/csi/vol-1", &api.CSIVolume{ID: "vol-1", Secrets: api.CSISecrets{"password": "hunter2"}})
+	server := newTestPluginServer(t, f, "reveal_secrets = true")
+
+	rows := testQuery{
+		table:   "nomad_volume",
+		columns: []string{"id", "secrets"},
+		quals:   equalsQuals(map[string]*proto.QualValue{"id": stringQual("vol-1")}),
+	}.mustExecute(t, server)
+	if len(rows) != 1 || rows[0].json("secrets") != `{"password":"hunter2"}` {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+}
@@ -16,6 +16,9 @@ func convertNanoSecToTimestamp(_ context.Context, d *transform.TransformData) (i
 	case int64:
 		epochTime = d.Value.(int64)
 	case *int64:
+		if d.Value.(*int64) == nil {
+			return nil, nil
+		}
 		epochTime = *d.Value.(*int64)
 	}
 
@@ -24,3 +27,24 @@ func convertNanoSecToTimestamp(_ context.Context, d *transform.TransformData) (i
 	return unixTimestamp, nil
 
 }
+
+// zeroTimeToNil returns nil for unset timestamps so they are not rendered as
+// 0001-01-01.
+func zeroTimeToNil(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	switch t := d.Value.(type) {
+	case time.Time:
+		if t.IsZero() {
+			return nil, nil
+		}
+	case *time.Time:
+		if t == nil || t.IsZero() {
+			return nil, nil
+		}
+	}
+	return d.Value, nil
+}
+
+// pointerOf returns a pointer to a copy of the value.
+func pointerOf[A any](a A) *A {
+	return &a
+}